@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/joho/godotenv"
@@ -9,6 +10,7 @@ import (
 	_ "pr_task/docs"
 	"pr_task/internal/config"
 	handlers "pr_task/internal/handler"
+	"pr_task/internal/migrations"
 	"pr_task/internal/repository"
 	"pr_task/internal/routes"
 	services "pr_task/internal/service"
@@ -32,6 +34,7 @@ func LoadConfig() (*config.DB, error) {
 		DBName:     getEnv("DB_NAME", "pr_review_db"),
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
 		ServerPort: getEnv("SERVER_PORT", "8080"),
+		AdminToken: getEnv("ADMIN_TOKEN", ""),
 	}
 
 	maxOpenConns, err := strconv.Atoi(getEnv("DB_MAX_OPEN_CONNS", "25"))
@@ -52,6 +55,36 @@ func LoadConfig() (*config.DB, error) {
 	}
 	configDB.DBConnMaxLifetime = connMaxLifetime
 
+	jobWorkers, err := strconv.Atoi(getEnv("JOB_WORKERS", "2"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_WORKERS: %v", err)
+	}
+	configDB.JobWorkers = jobWorkers
+
+	jobPollInterval, err := time.ParseDuration(getEnv("JOB_POLL_INTERVAL", "1s"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_POLL_INTERVAL: %v", err)
+	}
+	configDB.JobPollInterval = jobPollInterval
+
+	jobLease, err := time.ParseDuration(getEnv("JOB_LEASE", "5m"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_LEASE: %v", err)
+	}
+	configDB.JobLease = jobLease
+
+	jobMaxAttempts, err := strconv.Atoi(getEnv("JOB_MAX_ATTEMPTS", "3"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JOB_MAX_ATTEMPTS: %v", err)
+	}
+	configDB.JobMaxAttempts = jobMaxAttempts
+
+	migrateOnStart, err := strconv.ParseBool(getEnv("MIGRATE_ON_START", "true"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid MIGRATE_ON_START: %v", err)
+	}
+	configDB.MigrateOnStart = migrateOnStart
+
 	return configDB, nil
 }
 
@@ -85,6 +118,52 @@ func createDBConnection(config *config.DB) (*sql.DB, error) {
 	return db, nil
 }
 
+// runMigrateCommand обрабатывает подкоманду migrate: up, down [N] (по умолчанию одна миграция) и status
+func runMigrateCommand(migrator *migrations.Migrator, args []string) error {
+	ctx := context.Background()
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up|down [N]|status")
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("Applied %d migration(s)", applied)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			n, err := strconv.Atoi(args[1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid number of migrations to revert: %s", args[1])
+			}
+			steps = n
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		log.Printf("Reverted %d migration(s)", reverted)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q, expected up, down or status", args[0])
+	}
+	return nil
+}
+
 // @title PR Reviewer Assignment Service
 // @version 1.0.0
 // @description Сервис автоматического назначения ревьюверов для Pull Request'ов
@@ -109,6 +188,26 @@ func main() {
 		}
 	}(db)
 
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrateCommand(migrator, os.Args[2:]); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		return
+	}
+
+	if configDB.MigrateOnStart {
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			log.Fatalf("Failed to apply migrations: %v", err)
+		}
+		log.Printf("Database schema is up to date, applied %d migration(s)", applied)
+	}
+
 	e := echo.New()
 
 	e.Use(middleware.LoggerWithConfig(middleware.LoggerConfig{
@@ -128,7 +227,18 @@ func main() {
 
 	repo := repository.NewPostgresRepository(db)
 	service := services.NewService(repo)
-	handler := handlers.NewHandler(service)
+	handler := handlers.NewHandler(service, configDB.AdminToken)
+
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	runner := services.NewJobRunner(repo, services.JobRunnerConfig{
+		Workers:      configDB.JobWorkers,
+		PollInterval: configDB.JobPollInterval,
+		Lease:        configDB.JobLease,
+		MaxAttempts:  configDB.JobMaxAttempts,
+	})
+	go runner.Run(jobCtx)
 
 	routes.RegisterRoutes(e, handler)
 
@@ -1,4 +1,5 @@
-// Package docs Code generated by swaggo/swag. DO NOT EDIT
+// Code generated by swaggo/swag. DO NOT EDIT.
+
 package docs
 
 import "github.com/swaggo/swag"
@@ -15,75 +16,21 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/health": {
+        "/codeowners": {
             "get": {
-                "description": "Возвращает статус сервиса",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Правила в порядке применения: файл принадлежит последнему совпавшему правилу",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Health"
+                    "CodeOwners"
                 ],
-                "summary": "Проверка здоровья",
+                "summary": "Получить реестр владельцев кода",
                 "responses": {
                     "200": {
-                        "description": "Статус сервиса",
-                        "schema": {
-                            "$ref": "#/definitions/handler.HealthResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/pullRequest/create": {
-            "post": {
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "PullRequests"
-                ],
-                "parameters": [
-                    {
-                        "description": "Данные PR",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "description": "Реестр владельцев",
                         "schema": {
-                            "$ref": "#/definitions/dto.CreatePullRequestRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "PR создан",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "400": {
-                        "description": "Неверный запрос",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Автор/команда не найдены",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "PR уже существует",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
+                            "$ref": "#/definitions/dto.CodeOwnersResponse"
                         }
                     },
                     "500": {
@@ -93,11 +40,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/pullRequest/merge": {
-            "post": {
-                "description": "Идемпотентная операция мержа PR",
+            },
+            "put": {
+                "description": "Шаблоны в синтаксисе CODEOWNERS, владельцы - существующие пользователи и команды. Реестр заменяется целиком",
                 "consumes": [
                     "application/json"
                 ],
@@ -105,35 +50,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "PullRequests"
+                    "CodeOwners"
                 ],
-                "summary": "Пометить PR как MERGED",
+                "summary": "Заменить реестр владельцев кода",
                 "parameters": [
                     {
-                        "description": "Данные PR",
+                        "description": "Правила",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.MergePullRequestRequest"
+                            "$ref": "#/definitions/dto.CodeOwnersRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "PR в состоянии MERGED",
+                        "description": "Новый реестр",
                         "schema": {
-                            "$ref": "#/definitions/models.PullRequest"
+                            "$ref": "#/definitions/dto.CodeOwnersResponse"
                         }
                     },
                     "400": {
-                        "description": "Неверный запрос",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "PR не найден",
+                        "description": "Неверный шаблон или владелец",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -147,51 +86,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/pullRequest/reassign": {
+        "/codeowners/import": {
             "post": {
-                "description": "Заменяет ревьювера на другого из его команды",
+                "description": "Тело запроса - текст файла. @user - пользователь, @org/team - команда team; секции и адреса почты не поддерживаются",
                 "consumes": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "PullRequests"
+                    "CodeOwners"
                 ],
-                "summary": "Переназначить конкретного ревьювера",
+                "summary": "Импортировать файл CODEOWNERS",
                 "parameters": [
                     {
-                        "description": "Данные для переназначения",
+                        "description": "Содержимое CODEOWNERS",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.ReassignReviewerRequest"
+                            "type": "string"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Переназначение выполнено",
+                        "description": "Новый реестр",
                         "schema": {
-                            "$ref": "#/definitions/models.ReassignResponse"
+                            "$ref": "#/definitions/dto.CodeOwnersResponse"
                         }
                     },
                     "400": {
-                        "description": "Неверный запрос",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "PR или пользователь не найден",
-                        "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Нарушение доменных правил",
+                        "description": "Ошибка разбора или неизвестный владелец",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -205,9 +132,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/stats/overall": {
+        "/health": {
             "get": {
-                "description": "Возвращает общую статистику системы",
+                "description": "Возвращает статус сервиса",
                 "consumes": [
                     "application/json"
                 ],
@@ -215,73 +142,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Statistics"
+                    "Health"
                 ],
-                "summary": "Получить общую статистику",
+                "summary": "Проверка здоровья",
                 "responses": {
                     "200": {
-                        "description": "Общая статистика",
-                        "schema": {
-                            "$ref": "#/definitions/handler.OverallStatsResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Внутренняя ошибка сервера",
+                        "description": "Статус сервиса",
                         "schema": {
-                            "$ref": "#/definitions/errors.ErrorResponse"
+                            "$ref": "#/definitions/dto.HealthResponse"
                         }
                     }
                 }
             }
         },
-        "/stats/prs": {
+        "/jobs/{job_id}": {
             "get": {
-                "description": "Возвращает количество ревьюверов по каждому PR",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Возвращает статус (PENDING, RUNNING, SUCCEEDED, FAILED), прогресс, результат и ошибку фоновой задачи",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Statistics"
+                    "Jobs"
+                ],
+                "summary": "Получить фоновую задачу",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор задачи",
+                        "name": "job_id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Получить статистику по PR",
                 "responses": {
                     "200": {
-                        "description": "Статистика по PR",
+                        "description": "Задача",
                         "schema": {
-                            "$ref": "#/definitions/handler.PRReviewStatsResponse"
+                            "$ref": "#/definitions/dto.JobResponse"
                         }
                     },
-                    "500": {
-                        "description": "Внутренняя ошибка сервера",
+                    "404": {
+                        "description": "Задача не найдена",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/stats/users": {
-            "get": {
-                "description": "Возвращает количество назначений по каждому пользователю",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Statistics"
-                ],
-                "summary": "Получить статистику по ревьюверам",
-                "responses": {
-                    "200": {
-                        "description": "Статистика по пользователям",
-                        "schema": {
-                            "$ref": "#/definitions/handler.UserReviewStatsResponse"
-                        }
                     },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
@@ -292,9 +196,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/add": {
+        "/pullRequest/close": {
             "post": {
-                "description": "Создает команду и обновляет/создает пользователей",
+                "description": "Идемпотентная операция. Закрытые PR не попадают в /users/getReview и не учитываются в нагрузке ревьюверов",
                 "consumes": [
                     "application/json"
                 ],
@@ -302,30 +206,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Teams"
+                    "PullRequests"
                 ],
-                "summary": "Создать команду с участниками",
+                "summary": "Закрыть PR без мержа",
                 "parameters": [
                     {
-                        "description": "Данные команды",
+                        "description": "Данные PR",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Team"
+                            "$ref": "#/definitions/dto.ClosePullRequestRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Команда создана",
+                    "200": {
+                        "description": "PR в состоянии CLOSED",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "properties": {
+                                "pr": {
+                                    "$ref": "#/definitions/dto.PullRequest"
+                                }
+                            }
                         }
                     },
                     "400": {
-                        "description": "Команда уже существует",
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "PR уже смержен",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -339,9 +259,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/team/get": {
-            "get": {
-                "description": "Возвращает команду по имени",
+        "/pullRequest/create": {
+            "post": {
+                "description": "С is_draft=true PR создаётся в статусе DRAFT без ревьюверов, они назначаются в /pullRequest/markReady.\nПо changed_files из реестра /codeowners назначается хотя бы один владелец каждой затронутой области\n(code_owner_reviewers), остальные места заполняются из команды автора. Области без доступного владельца - в uncovered_patterns.\nС required_tags сначала выбираются кандидаты с наибольшим числом совпавших тегов навыков, нагрузка учитывается среди равных.\nassignment_explanations объясняет выбор каждого ревьювера, как в /pullRequest/get",
                 "consumes": [
                     "application/json"
                 ],
@@ -349,23 +269,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Teams"
+                    "PullRequests"
                 ],
-                "summary": "Получить команду с участниками",
+                "summary": "Создать PR и автоматически назначить ревьюверов (по умолчанию max_reviewers команды)",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Уникальное имя команды",
-                        "name": "team_name",
-                        "in": "query",
-                        "required": true
+                        "description": "Данные PR",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePullRequestRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Объект команды",
+                    "201": {
+                        "description": "PR создан",
                         "schema": {
-                            "$ref": "#/definitions/models.Team"
+                            "type": "object",
+                            "properties": {
+                                "pr": {
+                                    "$ref": "#/definitions/dto.PullRequest"
+                                }
+                            }
                         }
                     },
                     "400": {
@@ -375,7 +302,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Команда не найдена",
+                        "description": "Автор/команда не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "PR уже существует",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -389,9 +322,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/getReview": {
+        "/pullRequest/get": {
             "get": {
-                "description": "Возвращает список PR для ревью пользователя",
+                "description": "Для каждого автоматически назначенного ревьювера assignment_explanations содержит стратегию и команду выбора,\nразмер пула кандидатов, нагрузку ревьювера, совпавшие теги, недавние ревью PR автора\nи исключённых участников по причинам (author, already_assigned, inactive, on_leave, at_capacity)",
                 "consumes": [
                     "application/json"
                 ],
@@ -399,23 +332,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "PullRequests"
                 ],
-                "summary": "Получить PR'ы, где пользователь назначен ревьювером",
+                "summary": "Получить PR с ревьюверами и объяснением их выбора",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Идентификатор пользователя",
-                        "name": "user_id",
+                        "description": "Идентификатор PR",
+                        "name": "pull_request_id",
                         "in": "query",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список PR'ов пользователя",
+                        "description": "PR",
                         "schema": {
-                            "$ref": "#/definitions/models.UserReviewResponse"
+                            "type": "object",
+                            "properties": {
+                                "pr": {
+                                    "$ref": "#/definitions/dto.PullRequest"
+                                }
+                            }
                         }
                     },
                     "400": {
@@ -425,7 +363,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Пользователь не найден",
+                        "description": "PR не найден",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -439,9 +377,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/massDeactivate": {
+        "/pullRequest/markReady": {
             "post": {
-                "description": "Деактивирует всех пользователей команды и безопасно переназначает ревьюверов открытых PR",
+                "description": "Назначает ревьюверов по текущим настройкам команды автора (по умолчанию max_reviewers). Идемпотентна для открытых PR",
                 "consumes": [
                     "application/json"
                 ],
@@ -449,25 +387,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "PullRequests"
                 ],
-                "summary": "Массовая деактивация пользователей команды",
+                "summary": "Перевести черновик PR в OPEN",
                 "parameters": [
                     {
-                        "description": "Данные для массовой деактивации",
+                        "description": "Данные PR",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.MassDeactivationRequest"
+                            "$ref": "#/definitions/dto.MarkReadyRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Результат массовой деактивации",
+                        "description": "PR в состоянии OPEN",
                         "schema": {
-                            "$ref": "#/definitions/handler.MassDeactivationResponse"
+                            "type": "object",
+                            "properties": {
+                                "pr": {
+                                    "$ref": "#/definitions/dto.PullRequest"
+                                }
+                            }
                         }
                     },
                     "400": {
@@ -477,7 +420,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Команда не найдена",
+                        "description": "PR, автор или команда не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "PR смержен/закрыт или не хватает ревьюверов",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
@@ -491,9 +440,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/setIsActive": {
+        "/pullRequest/merge": {
             "post": {
-                "description": "Обновляет активность пользователя",
+                "description": "Идемпотентная операция мержа PR. Мерж проверяется по политике команды автора (одобрения, запрошенные изменения, одобрение тимлида),\nпри нарушении возвращается MERGE_BLOCKED со списком невыполненных условий в details.\nforce=true обходит политику, требует заголовок X-Admin-Token и сохраняется в PR вместе с обойдёнными условиями",
                 "consumes": [
                     "application/json"
                 ],
@@ -501,26 +450,36 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "PullRequests"
                 ],
-                "summary": "Установить флаг активности пользователя",
+                "summary": "Пометить PR как MERGED",
                 "parameters": [
                     {
-                        "description": "Данные пользователя",
+                        "description": "Данные PR",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.SetUserActiveRequest"
+                            "$ref": "#/definitions/dto.MergePullRequestRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Токен администратора для force",
+                        "name": "X-Admin-Token",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Обновлённый пользователь",
+                        "description": "PR в состоянии MERGED",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "properties": {
+                                "pr": {
+                                    "$ref": "#/definitions/dto.PullRequest"
+                                }
+                            }
                         }
                     },
                     "400": {
@@ -529,30 +488,2179 @@ const docTemplate = `{
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Пользователь не найден",
+                    "403": {
+                        "description": "force без токена администратора",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Внутренняя ошибка сервера",
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Мерж заблокирован политикой команды",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/reassign": {
+            "post": {
+                "description": "Заменяет ревьювера на другого из его команды, а если замены нет - из её запасных команд (fallback_team в ответе).\nexplanation объясняет выбор нового ревьювера, как в /pullRequest/get",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Переназначить конкретного ревьювера",
+                "parameters": [
+                    {
+                        "description": "Данные для переназначения",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReassignReviewerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Переназначение выполнено",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReassignResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR или пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Нарушение доменных правил",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/reopen": {
+            "post": {
+                "description": "Идемпотентная операция. Ставшие неактивными ревьюверы заменяются по стратегии команды",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Переоткрыть закрытый PR",
+                "parameters": [
+                    {
+                        "description": "Данные PR",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReopenPullRequestRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "PR в состоянии OPEN",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "pr": {
+                                    "$ref": "#/definitions/dto.PullRequest"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "PR уже смержен",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/pullRequest/review": {
+            "post": {
+                "description": "Назначенный ревьювер открытого PR выставляет состояние APPROVED, CHANGES_REQUESTED или COMMENTED",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PullRequests"
+                ],
+                "summary": "Оставить вердикт по PR",
+                "parameters": [
+                    {
+                        "description": "Вердикт",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SubmitReviewRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "PR с состояниями ревью",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "pr": {
+                                    "$ref": "#/definitions/dto.PullRequest"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "PR не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "PR не открыт или пользователь не назначен ревьювером",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/overall": {
+            "get": {
+                "description": "Возвращает общую статистику системы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Получить общую статистику",
+                "responses": {
+                    "200": {
+                        "description": "Общая статистика",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "stats": {
+                                    "$ref": "#/definitions/dto.OverallStatsResponse"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats/prs": {
+            "get": {
+                "description": "Возвращает количество ревьюверов по каждому PR",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Получить статистику по PR",
+                "responses": {
+                    "200": {
+                        "description": "Статистика по PR",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "stats": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/dto.PRReviewStatsResponse"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
                         "schema": {
                             "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.CreatePullRequestRequest": {
+        },
+        "/stats/users": {
+            "get": {
+                "description": "Возвращает количество назначений по каждому пользователю",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Получить статистику по ревьюверам",
+                "responses": {
+                    "200": {
+                        "description": "Статистика по пользователям",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "stats": {
+                                    "type": "array",
+                                    "items": {
+                                        "$ref": "#/definitions/dto.UserReviewStatsResponse"
+                                    }
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Все теги, назначенные пользователям, по алфавиту и число пользователей с каждым",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Список тегов навыков",
+                "responses": {
+                    "200": {
+                        "description": "Теги",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TagListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team": {
+            "delete": {
+                "description": "Удаляет команду и её участников. Открытые PR участников обрабатываются по open_pr_policy, как в PATCH /team.\nСмерженные и закрытые PR участников остаются без автора",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Удалить команду",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Имя команды",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "reject (по умолчанию), reassign или close",
+                        "name": "open_pr_policy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Новый автор PR для политики reassign",
+                        "name": "new_author_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Команда удалена",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteTeamResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или политика",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "У участников есть открытые PR",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "add создаёт или обновляет участников, участники других команд переводятся в эту команду,\nих открытые ревью обрабатываются по review_policy, как в /users/move. remove удаляет пользователей.\nОткрытые PR удаляемых участников обрабатываются по open_pr_policy: reject (по умолчанию) запрещает удаление,\nreassign передаёт их PR пользователю new_author_id, close закрывает их PR. Их места ревьюверов занимают участники команды автора PR",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Изменить состав команды",
+                "parameters": [
+                    {
+                        "description": "Изменения состава",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTeamMembersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённая команда",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateTeamMembersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос, политика или теги",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда или участник не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "У удаляемых участников есть открытые PR",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/add": {
+            "post": {
+                "description": "Создает команду и ее пользователей. Пользователей других команд переводят через /users/move или PATCH /team",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Создать команду с участниками",
+                "parameters": [
+                    {
+                        "description": "Данные команды",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Team"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Команда создана",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "team": {
+                                    "$ref": "#/definitions/models.Team"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Команда уже существует или неверные теги",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Участник уже состоит в другой команде",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/get": {
+            "get": {
+                "description": "Возвращает команду по имени",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Получить команду с участниками",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальное имя команды",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Объект команды",
+                        "schema": {
+                            "$ref": "#/definitions/models.Team"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/rename": {
+            "post": {
+                "description": "Меняет имя команды, участники, настройки и курсор ротации переходят к новому имени",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Переименовать команду",
+                "parameters": [
+                    {
+                        "description": "Старое и новое имя",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RenameTeamRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Переименованная команда",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "team": {
+                                    "$ref": "#/definitions/models.Team"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или имя занято",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/team/settings": {
+            "get": {
+                "description": "Возвращает стратегию выбора и допустимое число ревьюверов команды",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Получить настройки команды",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Уникальное имя команды",
+                        "name": "team_name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Настройки команды",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "settings": {
+                                    "$ref": "#/definitions/models.TeamSettings"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Меняет стратегию выбора ревьюверов (random, round_robin, least_loaded, weighted), допустимое число ревьюверов, политику мержа\nи предел открытых ревью участника по умолчанию (default_max_open_reviews, 0 снимает ограничение).\nfallback_teams - запасные команды по порядку, из которых добираются недостающие ревьюверы; пустой список их удаляет.\npairing_lookback - сколько последних PR автора учитывать: их ревьюверы выбираются в последнюю очередь (0 - не учитывать)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Обновить настройки команды",
+                "parameters": [
+                    {
+                        "description": "Настройки команды",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TeamSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённые настройки",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "settings": {
+                                    "$ref": "#/definitions/models.TeamSettings"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или неизвестная стратегия",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/teams": {
+            "get": {
+                "description": "Возвращает команды по алфавиту вместе с участниками и общее число команд",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Список команд",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (1..100, по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение от начала списка",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница команд",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TeamListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры пагинации",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Возвращает пользователей по возрастанию user_id с фильтрами по команде, активности и подстроке имени.\nСледующая страница запрашивается с cursor из next_cursor предыдущего ответа",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Поиск пользователей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Команда",
+                        "name": "team_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Флаг активности",
+                        "name": "is_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока имени без учёта регистра",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тег навыка",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "user_id, после которого начинается страница",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Размер страницы (1..100, по умолчанию 20)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница пользователей",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Открытые PR и черновики пользователя запрещают удаление (HAS_OPEN_PRS), если open_pr_policy не reassign\n(PR передаются new_author_id) или close (PR закрываются). Открытые ревью пользователя передаются участникам команды автора PR.\nСмерженные и закрытые PR пользователя остаются без автора",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Удалить пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "reject (по умолчанию), reassign или close",
+                        "name": "open_pr_policy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Новый автор PR для политики reassign",
+                        "name": "new_author_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь удалён",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeleteUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или политика",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "У пользователя есть открытые PR",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Обновляет username пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Изменить имя пользователя",
+                "parameters": [
+                    {
+                        "description": "Пользователь и новое имя",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённый пользователь",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user": {
+                                    "$ref": "#/definitions/models.User"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/get": {
+            "get": {
+                "description": "Возвращает пользователя по идентификатору",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user": {
+                                    "$ref": "#/definitions/models.User"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/getReview": {
+            "get": {
+                "description": "Возвращает список PR для ревью пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить PR'ы, где пользователь назначен ревьювером",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список PR'ов пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/leave": {
+            "get": {
+                "description": "Возвращает все периоды отсутствия пользователя по возрастанию даты начала",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Получить периоды отсутствия",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Периоды отсутствия",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserLeavesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "В даты периода (включительно) пользователь не выбирается ревьювером и заменой при переназначении.\nС auto_reassign открытые ревью пользователя передаются участникам его команды, когда период начинается;\nесли период уже начался, передача выполняется сразу и возвращается в reassignments и not_reassigned_prs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Добавить период отсутствия",
+                "parameters": [
+                    {
+                        "description": "Период отсутствия",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateUserLeaveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Период добавлен",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserLeaveResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или даты",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Период пересекается с существующим",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Пользователь снова выбирается ревьювером в эти даты. Уже переданные ревью ему не возвращаются",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Удалить период отсутствия",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор периода",
+                        "name": "leave_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Удалённый период",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "leave": {
+                                    "$ref": "#/definitions/models.UserLeave"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Период не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/massActivate": {
+            "post": {
+                "description": "Активирует пользователей команды (все неактивные или только user_ids).\nС operation_id отменяет массовую деактивацию: активирует ровно тех, кого она деактивировала,\nа с restore_reviewers=true возвращает прежних ревьюверов открытым PR, состав которых с тех пор не менялся",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Массовая активация пользователей",
+                "parameters": [
+                    {
+                        "description": "Данные для массовой активации",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MassActivationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат массовой активации",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MassActivationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда или операция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Операция уже отменена",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/massDeactivate": {
+            "post": {
+                "description": "Ставит в очередь фоновую задачу, которая деактивирует всех пользователей команды и переназначает ревьюверов открытых PR\nв одной транзакции: при ошибке изменения не применяются. Статус и результат задачи доступны по /jobs/{job_id}.\nС dry_run=true синхронно возвращает план (кого деактивировать и новые списки ревьюверов), ничего не записывая.\nКаждое изменение PR содержит explanations - объяснения выбора новых ревьюверов",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Массовая деактивация пользователей команды",
+                "parameters": [
+                    {
+                        "description": "Данные для массовой деактивации",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MassDeactivationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "План массовой деактивации (dry_run)",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MassDeactivationResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Задача поставлена в очередь",
+                        "schema": {
+                            "$ref": "#/definitions/dto.JobResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Команда не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/move": {
+            "post": {
+                "description": "С review_policy=reassign (по умолчанию) каждое открытое ревью пользователя передаётся участнику прежней команды по её стратегии,\nвсе замены возвращаются в reassignments. PR без подходящей замены перечислены в not_reassigned_prs, пользователь остаётся в них ревьювером.\nС review_policy=keep открытые ревью остаются за пользователем",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Перевести пользователя в другую команду",
+                "parameters": [
+                    {
+                        "description": "Пользователь и новая команда",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.MoveUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь переведён",
+                        "schema": {
+                            "$ref": "#/definitions/dto.MoveUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или политика",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь или команда не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/setIsActive": {
+            "post": {
+                "description": "Обновляет активность пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Установить флаг активности пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetUserActiveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённый пользователь",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user": {
+                                    "$ref": "#/definitions/models.User"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/setMaxOpenReviews": {
+            "post": {
+                "description": "Участник, у которого открытых ревью не меньше предела, не выбирается ревьювером. 0 возвращает предел команды по умолчанию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Задать предел открытых ревью пользователя",
+                "parameters": [
+                    {
+                        "description": "Пользователь и предел",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetMaxOpenReviewsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённый пользователь",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user": {
+                                    "$ref": "#/definitions/models.User"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/setTags": {
+            "post": {
+                "description": "Теги приводятся к нижнему регистру, повторы убираются. Пустой список удаляет все теги",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Задать теги навыков пользователя",
+                "parameters": [
+                    {
+                        "description": "Пользователь и теги",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.SetUserTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновлённый пользователь",
+                        "schema": {
+                            "type": "object",
+                            "properties": {
+                                "user": {
+                                    "$ref": "#/definitions/models.User"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный запрос или теги",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.ClosePullRequestRequest": {
+            "type": "object",
+            "required": [
+                "pull_request_id"
+            ],
+            "properties": {
+                "pull_request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CodeOwnersRequest": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CodeOwnerRule"
+                    }
+                }
+            }
+        },
+        "dto.CodeOwnersResponse": {
+            "type": "object",
+            "properties": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CodeOwnerRule"
+                    }
+                }
+            }
+        },
+        "dto.CreatePullRequestRequest": {
+            "type": "object",
+            "required": [
+                "author_id",
+                "pull_request_id",
+                "pull_request_name"
+            ],
+            "properties": {
+                "author_id": {
+                    "type": "string"
+                },
+                "changed_files": {
+                    "description": "ChangedFiles - пути изменённых файлов, по ним из реестра владельцев назначается хотя бы один владелец каждой затронутой области",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "internal/service/service_impl.go"
+                    ]
+                },
+                "is_draft": {
+                    "type": "boolean"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "pull_request_name": {
+                    "type": "string"
+                },
+                "required_tags": {
+                    "description": "RequiredTags - навыки, нужные для ревью: кандидаты с большим числом совпавших тегов выбираются раньше",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "go",
+                        "postgres"
+                    ]
+                },
+                "reviewer_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreateUserLeaveRequest": {
+            "type": "object",
+            "required": [
+                "ends_on",
+                "starts_on",
+                "user_id"
+            ],
+            "properties": {
+                "auto_reassign": {
+                    "type": "boolean"
+                },
+                "ends_on": {
+                    "type": "string",
+                    "example": "2025-07-14"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "vacation"
+                },
+                "starts_on": {
+                    "type": "string",
+                    "example": "2025-07-01"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "u2"
+                }
+            }
+        },
+        "dto.DeleteTeamResponse": {
+            "type": "object",
+            "properties": {
+                "closed_prs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "deleted_user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "u1",
+                        "u2"
+                    ]
+                },
+                "pr_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PRReviewersChange"
+                    }
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "backend"
+                },
+                "transferred_prs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.DeleteUserResponse": {
+            "type": "object",
+            "properties": {
+                "closed_prs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pr_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PRReviewersChange"
+                    }
+                },
+                "transferred_prs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "u3"
+                }
+            }
+        },
+        "dto.HealthResponse": {
+            "type": "object",
+            "properties": {
+                "service": {
+                    "type": "string",
+                    "example": "PR Reviewer Assignment Service"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "healthy"
+                },
+                "timestamp": {
+                    "type": "string",
+                    "example": "2025-11-25T16:30:45Z"
+                }
+            }
+        },
+        "dto.JobResponse": {
+            "type": "object",
+            "properties": {
+                "job": {
+                    "$ref": "#/definitions/models.Job"
+                }
+            }
+        },
+        "dto.MarkReadyRequest": {
+            "type": "object",
+            "required": [
+                "pull_request_id"
+            ],
+            "properties": {
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "reviewer_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.MassActivationRequest": {
+            "type": "object",
+            "properties": {
+                "operation_id": {
+                    "type": "string",
+                    "example": "3f1c2a9e-7b4d-4e35-9a51-0c2d8e6f1b77"
+                },
+                "restore_reviewers": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "backend"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "u1",
+                        "u2"
+                    ]
+                }
+            }
+        },
+        "dto.MassActivationResponse": {
+            "type": "object",
+            "properties": {
+                "activated_user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "u1",
+                        "u2",
+                        "u3"
+                    ]
+                },
+                "activated_users": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "operation_id": {
+                    "type": "string",
+                    "example": "3f1c2a9e-7b4d-4e35-9a51-0c2d8e6f1b77"
+                },
+                "restored_prs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PRReviewersChange"
+                    }
+                },
+                "skipped_prs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pr-1002"
+                    ]
+                }
+            }
+        },
+        "dto.MassDeactivationPlan": {
+            "type": "object",
+            "properties": {
+                "deactivated_user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "u3",
+                        "u4"
+                    ]
+                },
+                "pr_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PRReviewersChange"
+                    }
+                }
+            }
+        },
+        "dto.MassDeactivationRequest": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "exclude_user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "u1",
+                        "u2"
+                    ]
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "backend"
+                }
+            }
+        },
+        "dto.MassDeactivationResponse": {
+            "type": "object",
+            "properties": {
+                "deactivated_users": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "operation_id": {
+                    "type": "string",
+                    "example": "3f1c2a9e-7b4d-4e35-9a51-0c2d8e6f1b77"
+                },
+                "plan": {
+                    "$ref": "#/definitions/dto.MassDeactivationPlan"
+                },
+                "pr_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PRReviewersChange"
+                    }
+                },
+                "processing_time_ms": {
+                    "type": "integer",
+                    "example": 85
+                },
+                "understaffed_prs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "pr-1001"
+                    ]
+                },
+                "updated_prs": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "dto.MergePullRequestRequest": {
+            "type": "object",
+            "required": [
+                "pull_request_id"
+            ],
+            "properties": {
+                "force": {
+                    "description": "Force мержит PR в обход политики команды, требует X-Admin-Token",
+                    "type": "boolean"
+                },
+                "merged_by": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MoveUserRequest": {
+            "type": "object",
+            "required": [
+                "team_name",
+                "user_id"
+            ],
+            "properties": {
+                "review_policy": {
+                    "type": "string",
+                    "example": "reassign"
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "frontend"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "u3"
+                }
+            }
+        },
+        "dto.MoveUserResponse": {
+            "type": "object",
+            "properties": {
+                "from_team": {
+                    "type": "string",
+                    "example": "backend"
+                },
+                "not_reassigned_prs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reassignments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewerReassignment"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "dto.OverallStatsResponse": {
+            "type": "object",
+            "properties": {
+                "active_users": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "avg_reviews_per_pr": {
+                    "type": "number",
+                    "example": 2
+                },
+                "closed_prs": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "draft_prs": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "merged_prs": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "open_prs": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "total_prs": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "total_reviews": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "total_users": {
+                    "type": "integer",
+                    "example": 15
+                }
+            }
+        },
+        "dto.PRReviewStatsResponse": {
+            "type": "object",
+            "properties": {
+                "author_id": {
+                    "type": "string",
+                    "example": "u1"
+                },
+                "pr_id": {
+                    "type": "string",
+                    "example": "pr-1001"
+                },
+                "pr_name": {
+                    "type": "string",
+                    "example": "Add search feature"
+                },
+                "reviewer_count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "status": {
+                    "type": "string",
+                    "example": "OPEN"
+                }
+            }
+        },
+        "dto.PullRequest": {
+            "type": "object",
+            "properties": {
+                "assigned_reviewers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "assignment_explanations": {
+                    "description": "AssignmentExplanations - объяснения выбора ревьюверов, назначенных автоматически",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.AssignmentExplanation"
+                    }
+                },
+                "author_id": {
+                    "type": "string"
+                },
+                "bypassed_conditions": {
+                    "description": "BypassedConditions - условия политики мержа, не выполненные на момент принудительного мержа",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "changed_files": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "closedAt": {
+                    "type": "string"
+                },
+                "code_owner_reviewers": {
+                    "description": "CodeOwnerReviewers - владельцы, назначенные по изменённым файлам, и шаблоны их областей",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "fallback_reviewers": {
+                    "description": "FallbackReviewers - ревьюверы из запасных команд и их команды",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "force_merged": {
+                    "type": "boolean"
+                },
+                "mergedAt": {
+                    "type": "string"
+                },
+                "merged_by": {
+                    "type": "string"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "pull_request_name": {
+                    "type": "string"
+                },
+                "required_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reviews": {
+                    "description": "Reviews - состояние ревью каждого назначенного ревьювера в порядке AssignedReviewers",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PRReviewer"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "uncovered_patterns": {
+                    "description": "UncoveredPatterns - затронутые области, для которых не нашлось доступного владельца",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.PullRequestShort": {
             "type": "object",
-            "required": [
-                "author_id",
-                "pull_request_id",
-                "pull_request_name"
-            ],
             "properties": {
                 "author_id": {
                     "type": "string"
@@ -562,10 +2670,75 @@ const docTemplate = `{
                 },
                 "pull_request_name": {
                     "type": "string"
+                },
+                "review_state": {
+                    "type": "string",
+                    "example": "PENDING"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
                 }
             }
         },
-        "dto.MergePullRequestRequest": {
+        "dto.ReassignResponse": {
+            "type": "object",
+            "properties": {
+                "explanation": {
+                    "description": "Explanation - объяснение выбора нового ревьювера",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.AssignmentExplanation"
+                        }
+                    ]
+                },
+                "fallback_team": {
+                    "description": "FallbackTeam - запасная команда нового ревьювера, если в своей команде замены не нашлось",
+                    "type": "string"
+                },
+                "pr": {
+                    "$ref": "#/definitions/dto.PullRequest"
+                },
+                "replaced_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReassignReviewerRequest": {
+            "type": "object",
+            "required": [
+                "old_reviewer_id",
+                "pull_request_id"
+            ],
+            "properties": {
+                "old_reviewer_id": {
+                    "type": "string"
+                },
+                "pull_request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.RenameTeamRequest": {
+            "type": "object",
+            "required": [
+                "new_team_name",
+                "team_name"
+            ],
+            "properties": {
+                "new_team_name": {
+                    "type": "string",
+                    "example": "platform"
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "backend"
+                }
+            }
+        },
+        "dto.ReopenPullRequestRequest": {
             "type": "object",
             "required": [
                 "pull_request_id"
@@ -576,266 +2749,588 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.ReassignReviewerRequest": {
+        "dto.SetMaxOpenReviewsRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "max_open_reviews": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "u2"
+                }
+            }
+        },
+        "dto.SetUserActiveRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.SetUserTagsRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "go",
+                        "postgres"
+                    ]
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "u2"
+                }
+            }
+        },
+        "dto.SubmitReviewRequest": {
+            "type": "object",
+            "required": [
+                "pull_request_id",
+                "state",
+                "user_id"
+            ],
+            "properties": {
+                "pull_request_id": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "APPROVED"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.TagListResponse": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TagUsage"
+                    }
+                }
+            }
+        },
+        "dto.TagUsage": {
+            "type": "object",
+            "properties": {
+                "tag": {
+                    "type": "string",
+                    "example": "go"
+                },
+                "users": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "dto.TeamListResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "offset": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Team"
+                    }
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "dto.TeamSettingsRequest": {
+            "type": "object",
+            "required": [
+                "team_name"
+            ],
+            "properties": {
+                "block_on_changes_requested": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "default_max_open_reviews": {
+                    "description": "Предел открытых ревью участника по умолчанию, 0 снимает ограничение",
+                    "type": "integer",
+                    "example": 5
+                },
+                "fallback_teams": {
+                    "description": "Запасные команды по порядку, пустой список их удаляет",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "platform"
+                    ]
+                },
+                "max_reviewers": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "min_reviewers": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "pairing_lookback": {
+                    "description": "Сколько последних PR автора учитывать, чтобы не назначать одних и тех же ревьюверов, 0 - не учитывать",
+                    "type": "integer",
+                    "example": 5
+                },
+                "require_lead_approval": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "required_approvals": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reviewer_strategy": {
+                    "type": "string",
+                    "example": "least_loaded"
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "backend"
+                }
+            }
+        },
+        "dto.UpdateTeamMembersRequest": {
+            "type": "object",
+            "required": [
+                "team_name"
+            ],
+            "properties": {
+                "add": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TeamMember"
+                    }
+                },
+                "new_author_id": {
+                    "type": "string",
+                    "example": "u1"
+                },
+                "open_pr_policy": {
+                    "type": "string",
+                    "example": "reassign"
+                },
+                "remove": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "u3",
+                        "u4"
+                    ]
+                },
+                "review_policy": {
+                    "type": "string",
+                    "example": "reassign"
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "backend"
+                }
+            }
+        },
+        "dto.UpdateTeamMembersResponse": {
+            "type": "object",
+            "properties": {
+                "closed_prs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "moved_user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "u5"
+                    ]
+                },
+                "not_reassigned_prs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pr_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PRReviewersChange"
+                    }
+                },
+                "reassignments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewerReassignment"
+                    }
+                },
+                "removed_user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "u3",
+                        "u4"
+                    ]
+                },
+                "team": {
+                    "$ref": "#/definitions/models.Team"
+                },
+                "transferred_prs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dto.UpdateUserRequest": {
             "type": "object",
             "required": [
-                "old_reviewer_id",
-                "pull_request_id"
+                "user_id",
+                "username"
             ],
             "properties": {
-                "old_reviewer_id": {
-                    "type": "string"
+                "user_id": {
+                    "type": "string",
+                    "example": "u1"
                 },
-                "pull_request_id": {
-                    "type": "string"
+                "username": {
+                    "type": "string",
+                    "example": "Alice"
                 }
             }
         },
-        "dto.SetUserActiveRequest": {
+        "dto.UserLeaveResponse": {
             "type": "object",
-            "required": [
-                "user_id"
-            ],
             "properties": {
-                "is_active": {
-                    "type": "boolean"
+                "leave": {
+                    "$ref": "#/definitions/models.UserLeave"
                 },
-                "user_id": {
-                    "type": "string"
+                "not_reassigned_prs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reassignments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReviewerReassignment"
+                    }
                 }
             }
         },
-        "errors.ErrorResponse": {
+        "dto.UserLeavesResponse": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "object",
-                    "properties": {
-                        "code": {
-                            "type": "string"
-                        },
-                        "message": {
-                            "type": "string"
-                        }
+                "leaves": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserLeave"
                     }
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "handler.HealthResponse": {
+        "dto.UserListResponse": {
             "type": "object",
             "properties": {
-                "service": {
-                    "type": "string",
-                    "example": "PR Reviewer Assignment Service"
-                },
-                "status": {
+                "next_cursor": {
+                    "description": "NextCursor передаётся в cursor для следующей страницы, пустой на последней странице",
                     "type": "string",
-                    "example": "healthy"
+                    "example": "u20"
                 },
-                "timestamp": {
-                    "type": "string",
-                    "example": "2025-11-25T16:30:45Z"
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.User"
+                    }
                 }
             }
         },
-        "handler.MassDeactivationRequest": {
+        "dto.UserReviewResponse": {
             "type": "object",
             "properties": {
-                "exclude_user_ids": {
+                "pull_requests": {
                     "type": "array",
                     "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "u1",
-                        "u2"
-                    ]
+                        "$ref": "#/definitions/dto.PullRequestShort"
+                    }
                 },
-                "team_name": {
-                    "type": "string",
-                    "example": "backend"
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "handler.MassDeactivationResponse": {
+        "dto.UserReviewStatsResponse": {
             "type": "object",
             "properties": {
-                "deactivated_users": {
-                    "type": "integer",
-                    "example": 5
+                "at_capacity": {
+                    "type": "boolean"
                 },
-                "failed_prs": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "pr-1001"
-                    ]
+                "max_open_reviews": {
+                    "description": "Действующий предел открытых ревью, отсутствует, если ограничения нет",
+                    "type": "integer",
+                    "example": 3
                 },
-                "processing_time_ms": {
+                "open_reviews": {
                     "type": "integer",
-                    "example": 85
+                    "example": 2
                 },
-                "updated_prs": {
+                "review_count": {
                     "type": "integer",
-                    "example": 3
+                    "example": 5
+                },
+                "team_name": {
+                    "type": "string",
+                    "example": "backend"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "u1"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "Alice"
                 }
             }
         },
-        "handler.OverallStatsResponse": {
+        "errors.ErrorResponse": {
             "type": "object",
             "properties": {
-                "stats": {
+                "error": {
                     "type": "object",
                     "properties": {
-                        "active_users": {
-                            "type": "integer",
-                            "example": 12
-                        },
-                        "avg_reviews_per_pr": {
-                            "type": "number",
-                            "example": 2
-                        },
-                        "merged_prs": {
-                            "type": "integer",
-                            "example": 3
-                        },
-                        "open_prs": {
-                            "type": "integer",
-                            "example": 7
-                        },
-                        "total_prs": {
-                            "type": "integer",
-                            "example": 10
+                        "code": {
+                            "type": "string"
                         },
-                        "total_reviews": {
-                            "type": "integer",
-                            "example": 20
+                        "details": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         },
-                        "total_users": {
-                            "type": "integer",
-                            "example": 15
+                        "message": {
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "handler.PRReviewStatsResponse": {
+        "models.AssignmentExplanation": {
             "type": "object",
             "properties": {
-                "stats": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "properties": {
-                            "author_id": {
-                                "type": "string",
-                                "example": "u1"
-                            },
-                            "pr_id": {
-                                "type": "string",
-                                "example": "pr-1001"
-                            },
-                            "pr_name": {
-                                "type": "string",
-                                "example": "Add search feature"
-                            },
-                            "reviewer_count": {
-                                "type": "integer",
-                                "example": 2
-                            },
-                            "status": {
-                                "type": "string",
-                                "example": "OPEN"
-                            }
+                "candidate_pool": {
+                    "description": "CandidatePool - сколько кандидатов осталось после исключений",
+                    "type": "integer"
+                },
+                "code_owner_pattern": {
+                    "description": "CodeOwnerPattern - область реестра владельцев кода, за которую назначен ревьювер",
+                    "type": "string"
+                },
+                "exclusions": {
+                    "description": "Exclusions - исключённые участники по причинам (Exclusion*)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
                         }
                     }
+                },
+                "max_open_reviews": {
+                    "type": "integer"
+                },
+                "open_reviews": {
+                    "type": "integer"
+                },
+                "recent_reviews": {
+                    "type": "integer"
+                },
+                "strategy": {
+                    "type": "string"
+                },
+                "tag_overlap": {
+                    "description": "TagOverlap и RecentReviews - совпавшие требуемые теги и недавние ревью PR автора, по ним кандидаты упорядочиваются до стратегии",
+                    "type": "integer"
+                },
+                "team_name": {
+                    "type": "string"
                 }
             }
         },
-        "handler.UserReviewStatsResponse": {
+        "models.CodeOwnerRule": {
             "type": "object",
             "properties": {
-                "stats": {
+                "pattern": {
+                    "type": "string"
+                },
+                "teams": {
                     "type": "array",
                     "items": {
-                        "type": "object",
-                        "properties": {
-                            "review_count": {
-                                "type": "integer",
-                                "example": 5
-                            },
-                            "team_name": {
-                                "type": "string",
-                                "example": "backend"
-                            },
-                            "user_id": {
-                                "type": "string",
-                                "example": "u1"
-                            },
-                            "username": {
-                                "type": "string",
-                                "example": "Alice"
-                            }
-                        }
+                        "type": "string"
+                    }
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
         },
-        "models.PullRequest": {
+        "models.Job": {
             "type": "object",
             "properties": {
-                "assigned_reviewers": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "attempts": {
+                    "type": "integer"
                 },
-                "author_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "createdAt": {
+                "error": {
                     "type": "string"
                 },
-                "mergedAt": {
+                "finished_at": {
                     "type": "string"
                 },
-                "pull_request_id": {
+                "job_id": {
                     "type": "string"
                 },
-                "pull_request_name": {
+                "payload": {
+                    "type": "object"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "object"
+                },
+                "started_at": {
                     "type": "string"
                 },
                 "status": {
                     "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
-        "models.PullRequestShort": {
+        "models.PRReviewer": {
             "type": "object",
             "properties": {
-                "author_id": {
+                "assigned_at": {
                     "type": "string"
                 },
-                "pull_request_id": {
+                "assigned_by": {
                     "type": "string"
                 },
-                "pull_request_name": {
+                "fallback_team": {
+                    "description": "FallbackTeam - запасная команда, из которой назначен ревьювер",
                     "type": "string"
                 },
-                "status": {
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "models.ReassignResponse": {
+        "models.PRReviewersChange": {
             "type": "object",
             "properties": {
-                "pr": {
-                    "$ref": "#/definitions/models.PullRequest"
+                "after": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "replaced_by": {
+                "before": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "explanations": {
+                    "description": "Explanations - объяснения выбора новых ревьюверов",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.AssignmentExplanation"
+                    }
+                },
+                "fallback_teams": {
+                    "description": "FallbackTeams - запасные команды новых ревьюверов, назначенных не из своей команды",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "pr_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReviewerReassignment": {
+            "type": "object",
+            "properties": {
+                "fallback_team": {
+                    "type": "string"
+                },
+                "new_reviewer_id": {
+                    "type": "string"
+                },
+                "old_reviewer_id": {
+                    "type": "string"
+                },
+                "pr_id": {
                     "type": "string"
                 }
             }
@@ -860,6 +3355,16 @@ const docTemplate = `{
                 "is_active": {
                     "type": "boolean"
                 },
+                "is_lead": {
+                    "type": "boolean"
+                },
+                "tags": {
+                    "description": "Tags - теги навыков. Без тегов у существующего пользователя сохраняются прежние",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "user_id": {
                     "type": "string"
                 },
@@ -868,15 +3373,102 @@ const docTemplate = `{
                 }
             }
         },
-        "models.UserReviewResponse": {
+        "models.TeamSettings": {
             "type": "object",
             "properties": {
-                "pull_requests": {
+                "block_on_changes_requested": {
+                    "type": "boolean"
+                },
+                "default_max_open_reviews": {
+                    "description": "Предел открытых ревью участника по умолчанию, nil - без ограничения",
+                    "type": "integer"
+                },
+                "fallback_teams": {
+                    "description": "Запасные команды по порядку, из них добираются ревьюверы, которых не хватило в своей команде",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_reviewers": {
+                    "type": "integer"
+                },
+                "min_reviewers": {
+                    "type": "integer"
+                },
+                "pairing_lookback": {
+                    "description": "PairingLookback - сколько последних PR автора учитывать: их ревьюверы выбираются в последнюю очередь, 0 - не учитывать",
+                    "type": "integer"
+                },
+                "require_lead_approval": {
+                    "type": "boolean"
+                },
+                "required_approvals": {
+                    "description": "Политика мержа: минимум одобрений, запрет при запрошенных изменениях, обязательное одобрение тимлида",
+                    "type": "integer"
+                },
+                "reviewer_strategy": {
+                    "type": "string"
+                },
+                "team_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "is_lead": {
+                    "type": "boolean"
+                },
+                "max_open_reviews": {
+                    "description": "Личный предел открытых ревью, без него действует предел команды по умолчанию",
+                    "type": "integer"
+                },
+                "tags": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.PullRequestShort"
+                        "type": "string"
                     }
                 },
+                "team_name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserLeave": {
+            "type": "object",
+            "properties": {
+                "auto_reassign": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_on": {
+                    "type": "string"
+                },
+                "leave_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reassigned_at": {
+                    "type": "string"
+                },
+                "starts_on": {
+                    "type": "string"
+                },
                 "user_id": {
                     "type": "string"
                 }
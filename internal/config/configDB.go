@@ -10,7 +10,13 @@ type DB struct {
 	DBName            string
 	DBSSLMode         string
 	ServerPort        string
+	AdminToken        string
 	DBMaxOpenConns    int
 	DBMaxIdleConns    int
 	DBConnMaxLifetime time.Duration
+	JobWorkers        int
+	JobPollInterval   time.Duration
+	JobLease          time.Duration
+	JobMaxAttempts    int
+	MigrateOnStart    bool
 }
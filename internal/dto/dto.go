@@ -10,19 +10,187 @@ type TeamCreateRequest struct {
 	Members  []models.TeamMember `json:"members" validate:"required,min=1"`
 }
 
+type TeamListResponse struct {
+	Teams  []models.Team `json:"teams"`
+	Total  int           `json:"total" example:"12"`
+	Limit  int           `json:"limit" example:"20"`
+	Offset int           `json:"offset" example:"0"`
+}
+
+type RenameTeamRequest struct {
+	TeamName    string `json:"team_name" validate:"required" example:"backend"`
+	NewTeamName string `json:"new_team_name" validate:"required" example:"platform"`
+}
+
+// UpdateTeamMembersRequest добавляет и удаляет участников команды. Участник другой команды из Add переводится в эту команду,
+// его открытые ревью обрабатываются по ReviewPolicy, как в /users/move.
+// OpenPRPolicy определяет, что делать с открытыми PR удаляемых участников: reject (по умолчанию), reassign или close
+type UpdateTeamMembersRequest struct {
+	TeamName     string              `json:"team_name" validate:"required" example:"backend"`
+	Add          []models.TeamMember `json:"add,omitempty"`
+	Remove       []string            `json:"remove,omitempty" example:"u3,u4"`
+	OpenPRPolicy string              `json:"open_pr_policy,omitempty" example:"reassign"`
+	NewAuthorID  string              `json:"new_author_id,omitempty" example:"u1"`
+	ReviewPolicy string              `json:"review_policy,omitempty" example:"reassign"`
+}
+
+type UpdateTeamMembersResponse struct {
+	Team           *models.Team `json:"team"`
+	RemovedUserIDs []string     `json:"removed_user_ids,omitempty" example:"u3,u4"`
+	MovedUserIDs   []string     `json:"moved_user_ids,omitempty" example:"u5"`
+	models.OpenPRHandoff
+	models.ReviewHandoff
+}
+
+type DeleteTeamRequest struct {
+	TeamName     string `query:"team_name" validate:"required" example:"backend"`
+	OpenPRPolicy string `query:"open_pr_policy" example:"close"`
+	NewAuthorID  string `query:"new_author_id" example:"u5"`
+}
+
+type DeleteTeamResponse struct {
+	TeamName       string   `json:"team_name" example:"backend"`
+	DeletedUserIDs []string `json:"deleted_user_ids,omitempty" example:"u1,u2"`
+	models.OpenPRHandoff
+}
+
+type TeamSettingsRequest struct {
+	TeamName         string  `json:"team_name" validate:"required" example:"backend"`
+	ReviewerStrategy *string `json:"reviewer_strategy,omitempty" example:"least_loaded"`
+	MinReviewers     *int    `json:"min_reviewers,omitempty" example:"1"`
+	MaxReviewers     *int    `json:"max_reviewers,omitempty" example:"3"`
+
+	RequiredApprovals       *int  `json:"required_approvals,omitempty" example:"1"`
+	BlockOnChangesRequested *bool `json:"block_on_changes_requested,omitempty" example:"true"`
+	RequireLeadApproval     *bool `json:"require_lead_approval,omitempty" example:"false"`
+
+	// Предел открытых ревью участника по умолчанию, 0 снимает ограничение
+	DefaultMaxOpenReviews *int `json:"default_max_open_reviews,omitempty" example:"5"`
+	// Запасные команды по порядку, пустой список их удаляет
+	FallbackTeams []string `json:"fallback_teams,omitempty" example:"platform"`
+	// Сколько последних PR автора учитывать, чтобы не назначать одних и тех же ревьюверов, 0 - не учитывать
+	PairingLookback *int `json:"pairing_lookback,omitempty" example:"5"`
+}
+
+// SetMaxOpenReviewsRequest задаёт личный предел открытых ревью, 0 возвращает предел команды по умолчанию
+type SetMaxOpenReviewsRequest struct {
+	UserID         string `json:"user_id" validate:"required" example:"u2"`
+	MaxOpenReviews int    `json:"max_open_reviews" example:"3"`
+}
+
+// SetUserTagsRequest заменяет теги навыков пользователя, пустой список удаляет все теги
+type SetUserTagsRequest struct {
+	UserID string   `json:"user_id" validate:"required" example:"u2"`
+	Tags   []string `json:"tags" example:"go,postgres"`
+}
+
+type TagUsage struct {
+	Tag   string `json:"tag" example:"go"`
+	Users int    `json:"users" example:"4"`
+}
+
+type TagListResponse struct {
+	Tags []TagUsage `json:"tags"`
+}
+
 type SetUserActiveRequest struct {
 	UserID   string `json:"user_id" validate:"required"`
 	IsActive bool   `json:"is_active"`
 }
 
+type UserListResponse struct {
+	Users []models.User `json:"users"`
+	// NextCursor передаётся в cursor для следующей страницы, пустой на последней странице
+	NextCursor string `json:"next_cursor,omitempty" example:"u20"`
+}
+
+type UpdateUserRequest struct {
+	UserID   string `json:"user_id" validate:"required" example:"u1"`
+	Username string `json:"username" validate:"required" example:"Alice"`
+}
+
+type DeleteUserRequest struct {
+	UserID       string `query:"user_id" validate:"required" example:"u3"`
+	OpenPRPolicy string `query:"open_pr_policy" example:"close"`
+	NewAuthorID  string `query:"new_author_id" example:"u1"`
+}
+
+type DeleteUserResponse struct {
+	UserID string `json:"user_id" example:"u3"`
+	models.OpenPRHandoff
+}
+
+// MoveUserRequest переводит пользователя в другую команду.
+// ReviewPolicy: reassign (по умолчанию) передаёт его открытые ревью участникам прежней команды, keep оставляет их за ним
+type MoveUserRequest struct {
+	UserID       string `json:"user_id" validate:"required" example:"u3"`
+	TeamName     string `json:"team_name" validate:"required" example:"frontend"`
+	ReviewPolicy string `json:"review_policy,omitempty" example:"reassign"`
+}
+
+type MoveUserResponse struct {
+	User     *models.User `json:"user"`
+	FromTeam string       `json:"from_team" example:"backend"`
+	models.ReviewHandoff
+}
+
+// CreateUserLeaveRequest добавляет период отсутствия, даты в формате YYYY-MM-DD включительно.
+// AutoReassign передаёт открытые ревью пользователя его команде, когда период начинается
+type CreateUserLeaveRequest struct {
+	UserID       string `json:"user_id" validate:"required" example:"u2"`
+	StartsOn     string `json:"starts_on" validate:"required" example:"2025-07-01"`
+	EndsOn       string `json:"ends_on" validate:"required" example:"2025-07-14"`
+	Reason       string `json:"reason,omitempty" example:"vacation"`
+	AutoReassign bool   `json:"auto_reassign,omitempty"`
+}
+
+// UserLeaveResponse содержит созданный период и передачу ревью, если период уже начался
+type UserLeaveResponse struct {
+	Leave *models.UserLeave `json:"leave"`
+	models.ReviewHandoff
+}
+
+type UserLeavesResponse struct {
+	UserID string             `json:"user_id"`
+	Leaves []models.UserLeave `json:"leaves"`
+}
+
 type CreatePullRequestRequest struct {
 	PullRequestID   string `json:"pull_request_id" validate:"required"`
 	PullRequestName string `json:"pull_request_name" validate:"required"`
 	AuthorID        string `json:"author_id" validate:"required"`
+	ReviewerCount   *int   `json:"reviewer_count,omitempty"`
+	IsDraft         bool   `json:"is_draft,omitempty"`
+	// ChangedFiles - пути изменённых файлов, по ним из реестра владельцев назначается хотя бы один владелец каждой затронутой области
+	ChangedFiles []string `json:"changed_files,omitempty" example:"internal/service/service_impl.go"`
+	// RequiredTags - навыки, нужные для ревью: кандидаты с большим числом совпавших тегов выбираются раньше
+	RequiredTags []string `json:"required_tags,omitempty" example:"go,postgres"`
+}
+
+type SubmitReviewRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required"`
+	UserID        string `json:"user_id" validate:"required"`
+	State         string `json:"state" validate:"required" example:"APPROVED"`
+}
+
+type MarkReadyRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required"`
+	ReviewerCount *int   `json:"reviewer_count,omitempty"`
 }
 
 type MergePullRequestRequest struct {
 	PullRequestID string `json:"pull_request_id" validate:"required"`
+	// Force мержит PR в обход политики команды, требует X-Admin-Token
+	Force    bool   `json:"force,omitempty"`
+	MergedBy string `json:"merged_by,omitempty" example:"admin"`
+}
+
+type ClosePullRequestRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required"`
+}
+
+type ReopenPullRequestRequest struct {
+	PullRequestID string `json:"pull_request_id" validate:"required"`
 }
 
 type ReassignReviewerRequest struct {
@@ -37,13 +205,42 @@ type GetUserReviewRequest struct {
 type MassDeactivationRequest struct {
 	TeamName       string   `json:"team_name" example:"backend"`
 	ExcludeUserIDs []string `json:"exclude_user_ids,omitempty" example:"u1,u2"`
+	DryRun         bool     `json:"dry_run,omitempty" example:"false"`
+}
+
+type MassDeactivationPlan struct {
+	DeactivatedUserIDs []string                   `json:"deactivated_user_ids" example:"u3,u4"`
+	PRChanges          []models.PRReviewersChange `json:"pr_changes"`
 }
 
 type MassDeactivationResponse struct {
-	DeactivatedUsers int      `json:"deactivated_users" example:"5"`
-	UpdatedPRs       int      `json:"updated_prs" example:"3"`
-	FailedPRs        []string `json:"failed_prs,omitempty" example:"pr-1001"`
-	ProcessingTime   int64    `json:"processing_time_ms" example:"85"`
+	DeactivatedUsers int                        `json:"deactivated_users" example:"5"`
+	UpdatedPRs       int                        `json:"updated_prs" example:"3"`
+	UnderstaffedPRs  []string                   `json:"understaffed_prs,omitempty" example:"pr-1001"`
+	PRChanges        []models.PRReviewersChange `json:"pr_changes,omitempty"`
+	ProcessingTime   int64                      `json:"processing_time_ms" example:"85"`
+	DryRun           bool                       `json:"dry_run" example:"false"`
+	Plan             *MassDeactivationPlan      `json:"plan,omitempty"`
+	OperationID      string                     `json:"operation_id,omitempty" example:"3f1c2a9e-7b4d-4e35-9a51-0c2d8e6f1b77"`
+}
+
+type MassActivationRequest struct {
+	TeamName         string   `json:"team_name,omitempty" example:"backend"`
+	UserIDs          []string `json:"user_ids,omitempty" example:"u1,u2"`
+	OperationID      string   `json:"operation_id,omitempty" example:"3f1c2a9e-7b4d-4e35-9a51-0c2d8e6f1b77"`
+	RestoreReviewers bool     `json:"restore_reviewers,omitempty" example:"true"`
+}
+
+type MassActivationResponse struct {
+	ActivatedUsers   int                        `json:"activated_users" example:"3"`
+	ActivatedUserIDs []string                   `json:"activated_user_ids" example:"u1,u2,u3"`
+	OperationID      string                     `json:"operation_id,omitempty" example:"3f1c2a9e-7b4d-4e35-9a51-0c2d8e6f1b77"`
+	RestoredPRs      []models.PRReviewersChange `json:"restored_prs,omitempty"`
+	SkippedPRs       []string                   `json:"skipped_prs,omitempty" example:"pr-1002"`
+}
+
+type JobResponse struct {
+	Job models.Job `json:"job"`
 }
 
 type UserReviewStatsResponse struct {
@@ -51,6 +248,10 @@ type UserReviewStatsResponse struct {
 	Username    string `json:"username" example:"Alice"`
 	TeamName    string `json:"team_name" example:"backend"`
 	ReviewCount int    `json:"review_count" example:"5"`
+	OpenReviews int    `json:"open_reviews" example:"2"`
+	// Действующий предел открытых ревью, отсутствует, если ограничения нет
+	MaxOpenReviews *int `json:"max_open_reviews,omitempty" example:"3"`
+	AtCapacity     bool `json:"at_capacity"`
 }
 
 type PRReviewStatsResponse struct {
@@ -65,6 +266,8 @@ type OverallStatsResponse struct {
 	TotalPRs        int     `json:"total_prs" example:"10"`
 	OpenPRs         int     `json:"open_prs" example:"7"`
 	MergedPRs       int     `json:"merged_prs" example:"3"`
+	ClosedPRs       int     `json:"closed_prs" example:"1"`
+	DraftPRs        int     `json:"draft_prs" example:"2"`
 	TotalUsers      int     `json:"total_users" example:"15"`
 	ActiveUsers     int     `json:"active_users" example:"12"`
 	TotalReviews    int     `json:"total_reviews" example:"20"`
@@ -85,13 +288,41 @@ type PullRequest struct {
 	AssignedReviewers []string   `json:"assigned_reviewers"`
 	CreatedAt         *time.Time `json:"createdAt,omitempty"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	ClosedAt          *time.Time `json:"closedAt,omitempty"`
+	MergedBy          string     `json:"merged_by,omitempty"`
+	ForceMerged       bool       `json:"force_merged,omitempty"`
+	// BypassedConditions - условия политики мержа, не выполненные на момент принудительного мержа
+	BypassedConditions []string `json:"bypassed_conditions,omitempty"`
+	// Reviews - состояние ревью каждого назначенного ревьювера в порядке AssignedReviewers
+	Reviews []models.PRReviewer `json:"reviews,omitempty"`
+	// FallbackReviewers - ревьюверы из запасных команд и их команды
+	FallbackReviewers map[string]string `json:"fallback_reviewers,omitempty"`
+	ChangedFiles      []string          `json:"changed_files,omitempty"`
+	RequiredTags      []string          `json:"required_tags,omitempty"`
+	// CodeOwnerReviewers - владельцы, назначенные по изменённым файлам, и шаблоны их областей
+	CodeOwnerReviewers map[string]string `json:"code_owner_reviewers,omitempty"`
+	// UncoveredPatterns - затронутые области, для которых не нашлось доступного владельца
+	UncoveredPatterns []string `json:"uncovered_patterns,omitempty"`
+	// AssignmentExplanations - объяснения выбора ревьюверов, назначенных автоматически
+	AssignmentExplanations map[string]*models.AssignmentExplanation `json:"assignment_explanations,omitempty"`
+}
+
+// CodeOwnersRequest заменяет реестр владельцев кода целиком, порядок правил важен
+type CodeOwnersRequest struct {
+	Rules []models.CodeOwnerRule `json:"rules"`
+}
+
+type CodeOwnersResponse struct {
+	Rules []models.CodeOwnerRule `json:"rules"`
 }
 
 type PullRequestShort struct {
-	PullRequestID   string `json:"pull_request_id"`
-	PullRequestName string `json:"pull_request_name"`
-	AuthorID        string `json:"author_id"`
-	Status          string `json:"status"`
+	PullRequestID   string     `json:"pull_request_id"`
+	PullRequestName string     `json:"pull_request_name"`
+	AuthorID        string     `json:"author_id"`
+	Status          string     `json:"status"`
+	ReviewState     string     `json:"review_state" example:"PENDING"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
 }
 
 type UserReviewResponse struct {
@@ -102,4 +333,8 @@ type UserReviewResponse struct {
 type ReassignResponse struct {
 	PR         *PullRequest `json:"pr"`
 	ReplacedBy string       `json:"replaced_by"`
+	// FallbackTeam - запасная команда нового ревьювера, если в своей команде замены не нашлось
+	FallbackTeam string `json:"fallback_team,omitempty"`
+	// Explanation - объяснение выбора нового ревьювера
+	Explanation *models.AssignmentExplanation `json:"explanation,omitempty"`
 }
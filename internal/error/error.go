@@ -5,27 +5,90 @@ import (
 )
 
 const (
-	CodeTeamExists  = "TEAM_EXISTS"
-	CodePRExists    = "PR_EXISTS"
-	CodePRMerged    = "PR_MERGED"
-	CodeNotAssigned = "NOT_ASSIGNED"
-	CodeNoCandidate = "NO_CANDIDATE"
-	CodeNotFound    = "NOT_FOUND"
+	CodeTeamExists             = "TEAM_EXISTS"
+	CodePRExists               = "PR_EXISTS"
+	CodePRMerged               = "PR_MERGED"
+	CodePRClosed               = "PR_CLOSED"
+	CodePRDraft                = "PR_DRAFT"
+	CodeInvalidReviewState     = "INVALID_REVIEW_STATE"
+	CodeMergeBlocked           = "MERGE_BLOCKED"
+	CodeForbidden              = "FORBIDDEN"
+	CodeNotAssigned            = "NOT_ASSIGNED"
+	CodeNoCandidate            = "NO_CANDIDATE"
+	CodeNotFound               = "NOT_FOUND"
+	CodeInvalidStrategy        = "INVALID_STRATEGY"
+	CodeInvalidSettings        = "INVALID_SETTINGS"
+	CodeInvalidReviewerCount   = "INVALID_REVIEWER_COUNT"
+	CodeMassDeactivationFailed = "MASS_DEACTIVATION_FAILED"
+	CodeOperationUndone        = "OPERATION_UNDONE"
+	CodeHasOpenPRs             = "HAS_OPEN_PRS"
+	CodeInvalidPolicy          = "INVALID_POLICY"
+	CodeUserInOtherTeam        = "USER_IN_OTHER_TEAM"
+	CodeInvalidLeavePeriod     = "INVALID_LEAVE_PERIOD"
+	CodeLeaveOverlap           = "LEAVE_OVERLAP"
+	CodeInvalidFallbackTeams   = "INVALID_FALLBACK_TEAMS"
+	CodeInvalidCodeOwners      = "INVALID_CODEOWNERS"
+	CodeInvalidTags            = "INVALID_TAGS"
 )
 
 var (
-	ErrTeamExists  = errors.New("team already exists")
-	ErrPRExists    = errors.New("PR already exists")
-	ErrPRMerged    = errors.New("PR is merged")
-	ErrNotAssigned = errors.New("reviewer not assigned")
-	ErrNoCandidate = errors.New("no active replacement candidate")
-	ErrNotFound    = errors.New("resource not found")
+	ErrTeamExists             = errors.New("team already exists")
+	ErrPRExists               = errors.New("PR already exists")
+	ErrPRMerged               = errors.New("PR is merged")
+	ErrPRClosed               = errors.New("PR is closed")
+	ErrPRDraft                = errors.New("PR is a draft")
+	ErrInvalidReviewState     = errors.New("invalid review state")
+	ErrMergeBlocked           = errors.New("merge blocked by team policy")
+	ErrNotAssigned            = errors.New("reviewer not assigned")
+	ErrNoCandidate            = errors.New("no active replacement candidate")
+	ErrNotFound               = errors.New("resource not found")
+	ErrInvalidStrategy        = errors.New("unknown reviewer selection strategy")
+	ErrInvalidSettings        = errors.New("invalid team settings")
+	ErrInvalidReviewerCount   = errors.New("reviewer count is outside of team limits")
+	ErrMassDeactivationFailed = errors.New("mass deactivation failed, no changes were applied")
+	ErrOperationUndone        = errors.New("operation already undone")
+	ErrHasOpenPRs             = errors.New("users have open pull requests")
+	ErrInvalidPolicy          = errors.New("invalid open PR policy")
+	ErrInvalidReviewPolicy    = errors.New("invalid review policy")
+	ErrUserInOtherTeam        = errors.New("user already belongs to another team")
+	ErrInvalidLeavePeriod     = errors.New("invalid leave period")
+	ErrLeaveOverlap           = errors.New("leave period overlaps an existing one")
+	ErrInvalidFallbackTeams   = errors.New("fallback teams must exist, differ from the team and not repeat")
+	ErrInvalidCodeOwners      = errors.New("invalid code owners")
+	ErrInvalidTags            = errors.New("tags must be lowercase letters, digits and -_.+# up to 32 characters")
 )
 
+// MergeBlockedError перечисляет невыполненные условия политики мержа команды
+type MergeBlockedError struct {
+	Conditions []string
+}
+
+func (e *MergeBlockedError) Error() string {
+	return ErrMergeBlocked.Error()
+}
+
+func (e *MergeBlockedError) Unwrap() error {
+	return ErrMergeBlocked
+}
+
+// OpenPRsError перечисляет открытые PR, из-за которых пользователей нельзя удалить без политики reassign или close
+type OpenPRsError struct {
+	PRIDs []string
+}
+
+func (e *OpenPRsError) Error() string {
+	return ErrHasOpenPRs.Error()
+}
+
+func (e *OpenPRsError) Unwrap() error {
+	return ErrHasOpenPRs
+}
+
 type ErrorResponse struct {
 	Error struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
+		Code    string   `json:"code"`
+		Message string   `json:"message"`
+		Details []string `json:"details,omitempty"`
 	} `json:"error"`
 }
 
@@ -36,6 +99,16 @@ func NewErrorResponse(code, message string) ErrorResponse {
 	return resp
 }
 
+func NewErrorResponseWithDetails(code, message string, details []string) ErrorResponse {
+	resp := NewErrorResponse(code, message)
+	resp.Error.Details = details
+	return resp
+}
+
 func Is(err, target error) bool {
 	return errors.Is(err, target)
 }
+
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxCodeOwnersSize ограничивает размер импортируемого файла CODEOWNERS
+const maxCodeOwnersSize = 1 << 20
+
+// GetCodeOwners возвращает реестр владельцев кода
+// @Summary Получить реестр владельцев кода
+// @Description Правила в порядке применения: файл принадлежит последнему совпавшему правилу
+// @Tags CodeOwners
+// @Produce json
+// @Success 200 {object} dto.CodeOwnersResponse "Реестр владельцев"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /codeowners [get]
+func (h *Handler) GetCodeOwners(c echo.Context) error {
+	result, err := h.Service.GetCodeOwners(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to get code owners"))
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ReplaceCodeOwners заменяет реестр владельцев кода
+// @Summary Заменить реестр владельцев кода
+// @Description Шаблоны в синтаксисе CODEOWNERS, владельцы - существующие пользователи и команды. Реестр заменяется целиком
+// @Tags CodeOwners
+// @Accept json
+// @Produce json
+// @Param request body dto.CodeOwnersRequest true "Правила"
+// @Success 200 {object} dto.CodeOwnersResponse "Новый реестр"
+// @Failure 400 {object} errors.ErrorResponse "Неверный шаблон или владелец"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /codeowners [put]
+func (h *Handler) ReplaceCodeOwners(c echo.Context) error {
+	var req dto.CodeOwnersRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	result, err := h.Service.ReplaceCodeOwners(c.Request().Context(), req)
+	if err != nil {
+		return codeOwnersError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ImportCodeOwners заменяет реестр содержимым файла CODEOWNERS
+// @Summary Импортировать файл CODEOWNERS
+// @Description Тело запроса - текст файла. @user - пользователь, @org/team - команда team; секции и адреса почты не поддерживаются
+// @Tags CodeOwners
+// @Accept plain
+// @Produce json
+// @Param request body string true "Содержимое CODEOWNERS"
+// @Success 200 {object} dto.CodeOwnersResponse "Новый реестр"
+// @Failure 400 {object} errors.ErrorResponse "Ошибка разбора или неизвестный владелец"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /codeowners/import [post]
+func (h *Handler) ImportCodeOwners(c echo.Context) error {
+	content, err := io.ReadAll(io.LimitReader(c.Request().Body, maxCodeOwnersSize+1))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+	if len(content) > maxCodeOwnersSize {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "CODEOWNERS file is too large"))
+	}
+
+	result, err := h.Service.ImportCodeOwners(c.Request().Context(), string(content))
+	if err != nil {
+		return codeOwnersError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+func codeOwnersError(c echo.Context, err error) error {
+	if errors.Is(err, errors.ErrInvalidCodeOwners) {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidCodeOwners, err.Error()))
+	}
+	return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to update code owners"))
+}
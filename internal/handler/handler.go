@@ -1,13 +1,29 @@
 package handler
 
-import "pr_task/internal/service"
+import (
+	"crypto/subtle"
+	"pr_task/internal/service"
+
+	"github.com/labstack/echo/v4"
+)
 
 type Handler struct {
 	Service services.Service
+	// AdminToken открывает административные операции (принудительный мерж). Пустой токен их запрещает
+	AdminToken string
 }
 
-func NewHandler(service services.Service) *Handler {
+func NewHandler(service services.Service, adminToken string) *Handler {
 	return &Handler{
-		Service: service,
+		Service:    service,
+		AdminToken: adminToken,
+	}
+}
+
+func (h *Handler) isAdmin(c echo.Context) bool {
+	if h.AdminToken == "" {
+		return false
 	}
+	token := c.Request().Header.Get("X-Admin-Token")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.AdminToken)) == 1
 }
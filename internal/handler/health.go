@@ -14,7 +14,7 @@ import (
 // @Tags Health
 // @Accept json
 // @Produce json
-// @Success 200 {object} HealthResponse "Статус сервиса"
+// @Success 200 {object} dto.HealthResponse "Статус сервиса"
 // @Router /health [get]
 func (h *Handler) HealthCheck(c echo.Context) error {
 	return c.JSON(http.StatusOK, dto.HealthResponse{
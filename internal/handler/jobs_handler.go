@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetJob возвращает состояние фоновой задачи
+// @Summary Получить фоновую задачу
+// @Description Возвращает статус (PENDING, RUNNING, SUCCEEDED, FAILED), прогресс, результат и ошибку фоновой задачи
+// @Tags Jobs
+// @Produce json
+// @Param job_id path string true "Идентификатор задачи"
+// @Success 200 {object} dto.JobResponse "Задача"
+// @Failure 404 {object} errors.ErrorResponse "Задача не найдена"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /jobs/{job_id} [get]
+func (h *Handler) GetJob(c echo.Context) error {
+	job, err := h.Service.GetJob(c.Request().Context(), c.Param("job_id"))
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "Job not found"))
+		}
+		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to get job"))
+	}
+
+	return c.JSON(http.StatusOK, dto.JobResponse{Job: *job})
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateUserLeave добавляет период отсутствия пользователя
+// @Summary Добавить период отсутствия
+// @Description В даты периода (включительно) пользователь не выбирается ревьювером и заменой при переназначении.
+// @Description С auto_reassign открытые ревью пользователя передаются участникам его команды, когда период начинается;
+// @Description если период уже начался, передача выполняется сразу и возвращается в reassignments и not_reassigned_prs
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateUserLeaveRequest true "Период отсутствия"
+// @Success 201 {object} dto.UserLeaveResponse "Период добавлен"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос или даты"
+// @Failure 404 {object} errors.ErrorResponse "Пользователь не найден"
+// @Failure 409 {object} errors.ErrorResponse "Период пересекается с существующим"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/leave [post]
+func (h *Handler) CreateUserLeave(c echo.Context) error {
+	var req dto.CreateUserLeaveRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.UserID == "" || req.StartsOn == "" || req.EndsOn == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "user_id, starts_on and ends_on are required"))
+	}
+
+	result, err := h.Service.CreateUserLeave(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrInvalidLeavePeriod):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidLeavePeriod, "starts_on and ends_on must be YYYY-MM-DD dates, ends_on not before starts_on"))
+		case errors.Is(err, errors.ErrLeaveOverlap):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodeLeaveOverlap, err.Error()))
+		case errors.Is(err, errors.ErrNotFound):
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "User not found"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to create leave"))
+		}
+	}
+
+	return c.JSON(http.StatusCreated, result)
+}
+
+// GetUserLeaves возвращает периоды отсутствия пользователя
+// @Summary Получить периоды отсутствия
+// @Description Возвращает все периоды отсутствия пользователя по возрастанию даты начала
+// @Tags Users
+// @Produce json
+// @Param user_id query string true "Идентификатор пользователя" example:"u2"
+// @Success 200 {object} dto.UserLeavesResponse "Периоды отсутствия"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} errors.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/leave [get]
+func (h *Handler) GetUserLeaves(c echo.Context) error {
+	userID := c.QueryParam("user_id")
+	if userID == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "user_id is required"))
+	}
+
+	result, err := h.Service.GetUserLeaves(c.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "User not found"))
+		}
+		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to get leaves"))
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// DeleteUserLeave удаляет период отсутствия
+// @Summary Удалить период отсутствия
+// @Description Пользователь снова выбирается ревьювером в эти даты. Уже переданные ревью ему не возвращаются
+// @Tags Users
+// @Produce json
+// @Param leave_id query string true "Идентификатор периода"
+// @Success 200 {object} object{leave=models.UserLeave} "Удалённый период"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} errors.ErrorResponse "Период не найден"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/leave [delete]
+func (h *Handler) DeleteUserLeave(c echo.Context) error {
+	leaveID := c.QueryParam("leave_id")
+	if leaveID == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "leave_id is required"))
+	}
+
+	leave, err := h.Service.DeleteUserLeave(c.Request().Context(), leaveID)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "Leave not found"))
+		}
+		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to delete leave"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"leave": leave,
+	})
+}
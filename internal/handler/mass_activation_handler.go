@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MassActivateUsers массово активирует пользователей или отменяет массовую деактивацию
+// @Summary Массовая активация пользователей
+// @Description Активирует пользователей команды (все неактивные или только user_ids).
+// @Description С operation_id отменяет массовую деактивацию: активирует ровно тех, кого она деактивировала,
+// @Description а с restore_reviewers=true возвращает прежних ревьюверов открытым PR, состав которых с тех пор не менялся
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body dto.MassActivationRequest true "Данные для массовой активации"
+// @Success 200 {object} dto.MassActivationResponse "Результат массовой активации"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} errors.ErrorResponse "Команда или операция не найдена"
+// @Failure 409 {object} errors.ErrorResponse "Операция уже отменена"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/massActivate [post]
+func (h *Handler) MassActivateUsers(c echo.Context) error {
+	var req dto.MassActivationRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.TeamName == "" && req.OperationID == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "team_name or operation_id is required"))
+	}
+
+	result, err := h.Service.MassActivateUsers(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrNotFound):
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "Team or operation not found"))
+		case errors.Is(err, errors.ErrOperationUndone):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodeOperationUndone, "operation has already been undone"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to activate users"))
+		}
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -10,12 +10,16 @@ import (
 
 // MassDeactivateTeamUsers массово деактивирует пользователей команды
 // @Summary Массовая деактивация пользователей команды
-// @Description Деактивирует всех пользователей команды и безопасно переназначает ревьюверов открытых PR
+// @Description Ставит в очередь фоновую задачу, которая деактивирует всех пользователей команды и переназначает ревьюверов открытых PR
+// @Description в одной транзакции: при ошибке изменения не применяются. Статус и результат задачи доступны по /jobs/{job_id}.
+// @Description С dry_run=true синхронно возвращает план (кого деактивировать и новые списки ревьюверов), ничего не записывая.
+// @Description Каждое изменение PR содержит explanations - объяснения выбора новых ревьюверов
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Param request body MassDeactivationRequest true "Данные для массовой деактивации"
-// @Success 200 {object} MassDeactivationResponse "Результат массовой деактивации"
+// @Param request body dto.MassDeactivationRequest true "Данные для массовой деактивации"
+// @Success 200 {object} dto.MassDeactivationResponse "План массовой деактивации (dry_run)"
+// @Success 202 {object} dto.JobResponse "Задача поставлена в очередь"
 // @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
 // @Failure 404 {object} errors.ErrorResponse "Команда не найдена"
 // @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
@@ -30,9 +34,29 @@ func (h *Handler) MassDeactivateTeamUsers(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Team name is required"))
 	}
 
-	result, err := h.Service.MassDeactivateTeamUsers(c.Request().Context(), req.TeamName, req.ExcludeUserIDs)
+	if !req.DryRun {
+		job, err := h.Service.EnqueueMassDeactivation(c.Request().Context(), req)
+		if err != nil {
+			if errors.Is(err, errors.ErrNotFound) {
+				return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "Team not found"))
+			}
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to enqueue mass deactivation"))
+		}
+
+		c.Response().Header().Set(echo.HeaderLocation, "/jobs/"+job.JobID)
+		return c.JSON(http.StatusAccepted, dto.JobResponse{Job: *job})
+	}
+
+	result, err := h.Service.MassDeactivateTeamUsers(c.Request().Context(), req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", err.Error()))
+		switch {
+		case errors.Is(err, errors.ErrNotFound):
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "Team not found"))
+		case errors.Is(err, errors.ErrMassDeactivationFailed):
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse(errors.CodeMassDeactivationFailed, "Mass deactivation failed, no changes were applied"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to deactivate users"))
+		}
 	}
 
 	return c.JSON(http.StatusOK, result)
@@ -9,12 +9,18 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-// CreatePR @Summary Создать PR и автоматически назначить до 2 ревьюверов
+// CreatePR создаёт PR и назначает ревьюверов
+// @Summary Создать PR и автоматически назначить ревьюверов (по умолчанию max_reviewers команды)
+// @Description С is_draft=true PR создаётся в статусе DRAFT без ревьюверов, они назначаются в /pullRequest/markReady.
+// @Description По changed_files из реестра /codeowners назначается хотя бы один владелец каждой затронутой области
+// @Description (code_owner_reviewers), остальные места заполняются из команды автора. Области без доступного владельца - в uncovered_patterns.
+// @Description С required_tags сначала выбираются кандидаты с наибольшим числом совпавших тегов навыков, нагрузка учитывается среди равных.
+// @Description assignment_explanations объясняет выбор каждого ревьювера, как в /pullRequest/get
 // @Tags PullRequests
 // @Accept json
 // @Produce json
 // @Param request body dto.CreatePullRequestRequest true "Данные PR"
-// @Success 201 {object} map[string]interface{} "PR создан"
+// @Success 201 {object} object{pr=dto.PullRequest} "PR создан"
 // @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
 // @Failure 404 {object} errors.ErrorResponse "Автор/команда не найдены"
 // @Failure 409 {object} errors.ErrorResponse "PR уже существует"
@@ -31,13 +37,23 @@ func (h *Handler) CreatePR(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "All fields are required"))
 	}
 
-	pr, err := h.Service.CreatePullRequest(c.Request().Context(), req.PullRequestID, req.PullRequestName, req.AuthorID)
+	if req.IsDraft && req.ReviewerCount != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "reviewer_count is not allowed for draft PR, pass it to /pullRequest/markReady"))
+	}
+
+	pr, err := h.Service.CreatePullRequest(c.Request().Context(), req)
 	if err != nil {
 		switch {
 		case errors.Is(err, errors.ErrNotFound):
 			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "Author or team not found"))
 		case errors.Is(err, errors.ErrPRExists):
 			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRExists, "PR id already exists"))
+		case errors.Is(err, errors.ErrInvalidReviewerCount):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidReviewerCount, "reviewer_count is outside of team min_reviewers/max_reviewers"))
+		case errors.Is(err, errors.ErrInvalidTags):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidTags, err.Error()))
+		case errors.Is(err, errors.ErrNoCandidate):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodeNoCandidate, "not enough active reviewers in team"))
 		default:
 			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to create PR"))
 		}
@@ -48,16 +64,54 @@ func (h *Handler) CreatePR(c echo.Context) error {
 	})
 }
 
+// GetPR получает PR
+// @Summary Получить PR с ревьюверами и объяснением их выбора
+// @Description Для каждого автоматически назначенного ревьювера assignment_explanations содержит стратегию и команду выбора,
+// @Description размер пула кандидатов, нагрузку ревьювера, совпавшие теги, недавние ревью PR автора
+// @Description и исключённых участников по причинам (author, already_assigned, inactive, on_leave, at_capacity)
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param pull_request_id query string true "Идентификатор PR" example:"pr-1001"
+// @Success 200 {object} object{pr=dto.PullRequest} "PR"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} errors.ErrorResponse "PR не найден"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /pullRequest/get [get]
+func (h *Handler) GetPR(c echo.Context) error {
+	prID := c.QueryParam("pull_request_id")
+	if prID == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "pull_request_id is required"))
+	}
+
+	pr, err := h.Service.GetPullRequest(c.Request().Context(), prID)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "PR not found"))
+		}
+		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to get PR"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"pr": pr,
+	})
+}
+
 // MergePR помечает PR как MERGED
 // @Summary Пометить PR как MERGED
-// @Description Идемпотентная операция мержа PR
+// @Description Идемпотентная операция мержа PR. Мерж проверяется по политике команды автора (одобрения, запрошенные изменения, одобрение тимлида),
+// @Description при нарушении возвращается MERGE_BLOCKED со списком невыполненных условий в details.
+// @Description force=true обходит политику, требует заголовок X-Admin-Token и сохраняется в PR вместе с обойдёнными условиями
 // @Tags PullRequests
 // @Accept json
 // @Produce json
 // @Param request body dto.MergePullRequestRequest true "Данные PR"
-// @Success 200 {object} models.PullRequest "PR в состоянии MERGED"
+// @Param X-Admin-Token header string false "Токен администратора для force"
+// @Success 200 {object} object{pr=dto.PullRequest} "PR в состоянии MERGED"
 // @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 403 {object} errors.ErrorResponse "force без токена администратора"
 // @Failure 404 {object} errors.ErrorResponse "PR не найден"
+// @Failure 409 {object} errors.ErrorResponse "Мерж заблокирован политикой команды"
 // @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /pullRequest/merge [post]
 func (h *Handler) MergePR(c echo.Context) error {
@@ -71,12 +125,205 @@ func (h *Handler) MergePR(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "pull_request_id is required"))
 	}
 
-	pr, err := h.Service.MergePullRequest(c.Request().Context(), req.PullRequestID)
+	if req.Force && !h.isAdmin(c) {
+		return c.JSON(http.StatusForbidden, errors.NewErrorResponse(errors.CodeForbidden, "force merge requires a valid X-Admin-Token"))
+	}
+
+	pr, err := h.Service.MergePullRequest(c.Request().Context(), req)
 	if err != nil {
-		if errors.Is(err, errors.ErrNotFound) {
+		var blocked *errors.MergeBlockedError
+		switch {
+		case errors.As(err, &blocked):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponseWithDetails(errors.CodeMergeBlocked, "merge blocked by team policy", blocked.Conditions))
+		case errors.Is(err, errors.ErrNotFound):
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "PR not found"))
+		case errors.Is(err, errors.ErrPRClosed):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRClosed, "cannot merge closed PR"))
+		case errors.Is(err, errors.ErrPRDraft):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRDraft, "cannot merge draft PR"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to merge PR"))
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"pr": pr,
+	})
+}
+
+// ClosePR помечает PR как CLOSED
+// @Summary Закрыть PR без мержа
+// @Description Идемпотентная операция. Закрытые PR не попадают в /users/getReview и не учитываются в нагрузке ревьюверов
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param request body dto.ClosePullRequestRequest true "Данные PR"
+// @Success 200 {object} object{pr=dto.PullRequest} "PR в состоянии CLOSED"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} errors.ErrorResponse "PR не найден"
+// @Failure 409 {object} errors.ErrorResponse "PR уже смержен"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /pullRequest/close [post]
+func (h *Handler) ClosePR(c echo.Context) error {
+	var req dto.ClosePullRequestRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.PullRequestID == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "pull_request_id is required"))
+	}
+
+	pr, err := h.Service.ClosePullRequest(c.Request().Context(), req.PullRequestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrNotFound):
 			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "PR not found"))
+		case errors.Is(err, errors.ErrPRMerged):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRMerged, "cannot close merged PR"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to close PR"))
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"pr": pr,
+	})
+}
+
+// ReopenPR возвращает закрытый PR в OPEN
+// @Summary Переоткрыть закрытый PR
+// @Description Идемпотентная операция. Ставшие неактивными ревьюверы заменяются по стратегии команды
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param request body dto.ReopenPullRequestRequest true "Данные PR"
+// @Success 200 {object} object{pr=dto.PullRequest} "PR в состоянии OPEN"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} errors.ErrorResponse "PR не найден"
+// @Failure 409 {object} errors.ErrorResponse "PR уже смержен"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /pullRequest/reopen [post]
+func (h *Handler) ReopenPR(c echo.Context) error {
+	var req dto.ReopenPullRequestRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.PullRequestID == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "pull_request_id is required"))
+	}
+
+	pr, err := h.Service.ReopenPullRequest(c.Request().Context(), req.PullRequestID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrNotFound):
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "PR not found"))
+		case errors.Is(err, errors.ErrPRMerged):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRMerged, "cannot reopen merged PR"))
+		case errors.Is(err, errors.ErrPRDraft):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRDraft, "draft PR is not closed, use /pullRequest/markReady"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to reopen PR"))
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"pr": pr,
+	})
+}
+
+// MarkPRReady переводит черновик PR в OPEN
+// @Summary Перевести черновик PR в OPEN
+// @Description Назначает ревьюверов по текущим настройкам команды автора (по умолчанию max_reviewers). Идемпотентна для открытых PR
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param request body dto.MarkReadyRequest true "Данные PR"
+// @Success 200 {object} object{pr=dto.PullRequest} "PR в состоянии OPEN"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} errors.ErrorResponse "PR, автор или команда не найдены"
+// @Failure 409 {object} errors.ErrorResponse "PR смержен/закрыт или не хватает ревьюверов"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /pullRequest/markReady [post]
+func (h *Handler) MarkPRReady(c echo.Context) error {
+	var req dto.MarkReadyRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.PullRequestID == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "pull_request_id is required"))
+	}
+
+	pr, err := h.Service.MarkPullRequestReady(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrNotFound):
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "PR, author or team not found"))
+		case errors.Is(err, errors.ErrPRMerged):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRMerged, "PR is already merged"))
+		case errors.Is(err, errors.ErrPRClosed):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRClosed, "PR is closed"))
+		case errors.Is(err, errors.ErrInvalidReviewerCount):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidReviewerCount, "reviewer_count is outside of team min_reviewers/max_reviewers"))
+		case errors.Is(err, errors.ErrNoCandidate):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodeNoCandidate, "not enough active reviewers in team"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to mark PR ready"))
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"pr": pr,
+	})
+}
+
+// SubmitReview сохраняет вердикт ревьювера
+// @Summary Оставить вердикт по PR
+// @Description Назначенный ревьювер открытого PR выставляет состояние APPROVED, CHANGES_REQUESTED или COMMENTED
+// @Tags PullRequests
+// @Accept json
+// @Produce json
+// @Param request body dto.SubmitReviewRequest true "Вердикт"
+// @Success 200 {object} object{pr=dto.PullRequest} "PR с состояниями ревью"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} errors.ErrorResponse "PR не найден"
+// @Failure 409 {object} errors.ErrorResponse "PR не открыт или пользователь не назначен ревьювером"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /pullRequest/review [post]
+func (h *Handler) SubmitReview(c echo.Context) error {
+	var req dto.SubmitReviewRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.PullRequestID == "" || req.UserID == "" || req.State == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "pull_request_id, user_id and state are required"))
+	}
+
+	pr, err := h.Service.SubmitReview(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrInvalidReviewState):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidReviewState, "state must be APPROVED, CHANGES_REQUESTED or COMMENTED"))
+		case errors.Is(err, errors.ErrNotFound):
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "PR not found"))
+		case errors.Is(err, errors.ErrPRMerged):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRMerged, "cannot review merged PR"))
+		case errors.Is(err, errors.ErrPRClosed):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRClosed, "cannot review closed PR"))
+		case errors.Is(err, errors.ErrPRDraft):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRDraft, "cannot review draft PR"))
+		case errors.Is(err, errors.ErrNotAssigned):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodeNotAssigned, "user is not assigned to this PR"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to submit review"))
 		}
-		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to merge PR"))
 	}
 
 	return c.JSON(http.StatusOK, map[string]interface{}{
@@ -86,12 +333,13 @@ func (h *Handler) MergePR(c echo.Context) error {
 
 // ReassignReviewer переназначает ревьювера
 // @Summary Переназначить конкретного ревьювера
-// @Description Заменяет ревьювера на другого из его команды
+// @Description Заменяет ревьювера на другого из его команды, а если замены нет - из её запасных команд (fallback_team в ответе).
+// @Description explanation объясняет выбор нового ревьювера, как в /pullRequest/get
 // @Tags PullRequests
 // @Accept json
 // @Produce json
 // @Param request body dto.ReassignReviewerRequest true "Данные для переназначения"
-// @Success 200 {object} models.ReassignResponse "Переназначение выполнено"
+// @Success 200 {object} dto.ReassignResponse "Переназначение выполнено"
 // @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
 // @Failure 404 {object} errors.ErrorResponse "PR или пользователь не найден"
 // @Failure 409 {object} errors.ErrorResponse "Нарушение доменных правил"
@@ -115,10 +363,14 @@ func (h *Handler) ReassignReviewer(c echo.Context) error {
 			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "PR or user not found"))
 		case errors.Is(err, errors.ErrPRMerged):
 			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRMerged, "cannot reassign on merged PR"))
+		case errors.Is(err, errors.ErrPRClosed):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRClosed, "cannot reassign on closed PR"))
+		case errors.Is(err, errors.ErrPRDraft):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodePRDraft, "cannot reassign on draft PR"))
 		case errors.Is(err, errors.ErrNotAssigned):
 			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodeNotAssigned, "reviewer is not assigned to this PR"))
 		case errors.Is(err, errors.ErrNoCandidate):
-			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodeNoCandidate, "no active replacement candidate in team"))
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodeNoCandidate, "no active replacement candidate in team or its fallback teams"))
 		default:
 			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to reassign reviewer"))
 		}
@@ -13,7 +13,7 @@ import (
 // @Tags Statistics
 // @Accept json
 // @Produce json
-// @Success 200 {object} dto.UserReviewStatsResponse "Статистика по пользователям"
+// @Success 200 {object} object{stats=[]dto.UserReviewStatsResponse} "Статистика по пользователям"
 // @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /stats/users [get]
 func (h *Handler) GetUserReviewStats(c echo.Context) error {
@@ -33,7 +33,7 @@ func (h *Handler) GetUserReviewStats(c echo.Context) error {
 // @Tags Statistics
 // @Accept json
 // @Produce json
-// @Success 200 {object} dto.PRReviewStatsResponse "Статистика по PR"
+// @Success 200 {object} object{stats=[]dto.PRReviewStatsResponse} "Статистика по PR"
 // @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /stats/prs [get]
 func (h *Handler) GetPRReviewStats(c echo.Context) error {
@@ -53,7 +53,7 @@ func (h *Handler) GetPRReviewStats(c echo.Context) error {
 // @Tags Statistics
 // @Accept json
 // @Produce json
-// @Success 200 {object} dto.OverallStatsResponse "Общая статистика"
+// @Success 200 {object} object{stats=dto.OverallStatsResponse} "Общая статистика"
 // @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /stats/overall [get]
 func (h *Handler) GetOverallStats(c echo.Context) error {
@@ -6,17 +6,20 @@ import (
 	"pr_task/internal/dto"
 	errors "pr_task/internal/error"
 	"pr_task/internal/model"
+	"slices"
+	"strconv"
 )
 
 // AddTeam создает команду с участниками
 // @Summary Создать команду с участниками
-// @Description Создает команду и обновляет/создает пользователей
+// @Description Создает команду и ее пользователей. Пользователей других команд переводят через /users/move или PATCH /team
 // @Tags Teams
 // @Accept json
 // @Produce json
 // @Param request body models.Team true "Данные команды"
-// @Success 201 {object} map[string]interface{} "Команда создана"
-// @Failure 400 {object} errors.ErrorResponse "Команда уже существует"
+// @Success 201 {object} object{team=models.Team} "Команда создана"
+// @Failure 400 {object} errors.ErrorResponse "Команда уже существует или неверные теги"
+// @Failure 409 {object} errors.ErrorResponse "Участник уже состоит в другой команде"
 // @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /team/add [post]
 func (h *Handler) AddTeam(c echo.Context) error {
@@ -31,8 +34,13 @@ func (h *Handler) AddTeam(c echo.Context) error {
 
 	team, err := h.Service.CreateTeam(c.Request().Context(), models.Team{TeamName: req.TeamName, Members: req.Members})
 	if err != nil {
-		if errors.Is(err, errors.ErrTeamExists) {
+		switch {
+		case errors.Is(err, errors.ErrTeamExists):
 			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeTeamExists, "team_name already exists"))
+		case errors.Is(err, errors.ErrUserInOtherTeam):
+			return c.JSON(http.StatusConflict, errors.NewErrorResponse(errors.CodeUserInOtherTeam, "member already belongs to another team, use /users/move"))
+		case errors.Is(err, errors.ErrInvalidTags):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidTags, err.Error()))
 		}
 		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to create team"))
 	}
@@ -70,3 +78,252 @@ func (h *Handler) GetTeam(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, team)
 }
+
+// GetTeamSettings получает настройки команды
+// @Summary Получить настройки команды
+// @Description Возвращает стратегию выбора и допустимое число ревьюверов команды
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param team_name query string true "Уникальное имя команды" example:"backend"
+// @Success 200 {object} object{settings=models.TeamSettings} "Настройки команды"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} errors.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /team/settings [get]
+func (h *Handler) GetTeamSettings(c echo.Context) error {
+	teamName := c.QueryParam("team_name")
+	if teamName == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "team_name is required"))
+	}
+
+	settings, err := h.Service.GetTeamSettings(c.Request().Context(), teamName)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "Team not found"))
+		}
+		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to get team settings"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"settings": settings,
+	})
+}
+
+// UpdateTeamSettings обновляет настройки команды
+// @Summary Обновить настройки команды
+// @Description Меняет стратегию выбора ревьюверов (random, round_robin, least_loaded, weighted), допустимое число ревьюверов, политику мержа
+// @Description и предел открытых ревью участника по умолчанию (default_max_open_reviews, 0 снимает ограничение).
+// @Description fallback_teams - запасные команды по порядку, из которых добираются недостающие ревьюверы; пустой список их удаляет.
+// @Description pairing_lookback - сколько последних PR автора учитывать: их ревьюверы выбираются в последнюю очередь (0 - не учитывать)
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param request body dto.TeamSettingsRequest true "Настройки команды"
+// @Success 200 {object} object{settings=models.TeamSettings} "Обновлённые настройки"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос или неизвестная стратегия"
+// @Failure 404 {object} errors.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /team/settings [post]
+func (h *Handler) UpdateTeamSettings(c echo.Context) error {
+	var req dto.TeamSettingsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.TeamName == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "team_name is required"))
+	}
+
+	settings, err := h.Service.UpdateTeamSettings(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrInvalidStrategy):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidStrategy, "unknown reviewer_strategy"))
+		case errors.Is(err, errors.ErrInvalidFallbackTeams):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidFallbackTeams, err.Error()))
+		case errors.Is(err, errors.ErrInvalidSettings):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidSettings, "min_reviewers and required_approvals must be between 0 and max_reviewers, max_reviewers must be at least 1, default_max_open_reviews must not be negative, pairing_lookback must be between 0 and 100"))
+		case errors.Is(err, errors.ErrNotFound):
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "Team not found"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to update team settings"))
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"settings": settings,
+	})
+}
+
+// ListTeams возвращает страницу команд
+// @Summary Список команд
+// @Description Возвращает команды по алфавиту вместе с участниками и общее число команд
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param limit query int false "Размер страницы (1..100, по умолчанию 20)" example:"20"
+// @Param offset query int false "Смещение от начала списка" example:"0"
+// @Success 200 {object} dto.TeamListResponse "Страница команд"
+// @Failure 400 {object} errors.ErrorResponse "Неверные параметры пагинации"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /teams [get]
+func (h *Handler) ListTeams(c echo.Context) error {
+	limit, offset := 20, 0
+	if value := c.QueryParam("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 100 {
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "limit must be between 1 and 100"))
+		}
+		limit = n
+	}
+	if value := c.QueryParam("offset"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "offset must be a non-negative integer"))
+		}
+		offset = n
+	}
+
+	result, err := h.Service.ListTeams(c.Request().Context(), limit, offset)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to list teams"))
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// UpdateTeamMembers добавляет, удаляет и переводит участников команды
+// @Summary Изменить состав команды
+// @Description add создаёт или обновляет участников, участники других команд переводятся в эту команду,
+// @Description их открытые ревью обрабатываются по review_policy, как в /users/move. remove удаляет пользователей.
+// @Description Открытые PR удаляемых участников обрабатываются по open_pr_policy: reject (по умолчанию) запрещает удаление,
+// @Description reassign передаёт их PR пользователю new_author_id, close закрывает их PR. Их места ревьюверов занимают участники команды автора PR
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateTeamMembersRequest true "Изменения состава"
+// @Success 200 {object} dto.UpdateTeamMembersResponse "Обновлённая команда"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос, политика или теги"
+// @Failure 404 {object} errors.ErrorResponse "Команда или участник не найдены"
+// @Failure 409 {object} errors.ErrorResponse "У удаляемых участников есть открытые PR"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /team [patch]
+func (h *Handler) UpdateTeamMembers(c echo.Context) error {
+	var req dto.UpdateTeamMembersRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.TeamName == "" || (len(req.Add) == 0 && len(req.Remove) == 0) {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "team_name and add or remove are required"))
+	}
+	for _, member := range req.Add {
+		if member.UserID == "" || member.Username == "" {
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "user_id and username are required for added members"))
+		}
+		if slices.Contains(req.Remove, member.UserID) {
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "user cannot be both added and removed"))
+		}
+	}
+
+	result, err := h.Service.UpdateTeamMembers(c.Request().Context(), req)
+	if err != nil {
+		if errors.Is(err, errors.ErrInvalidTags) {
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidTags, err.Error()))
+		}
+		return h.userRemovalError(c, err, "Team or member not found", "Failed to update team members")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// RenameTeam переименовывает команду
+// @Summary Переименовать команду
+// @Description Меняет имя команды, участники, настройки и курсор ротации переходят к новому имени
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param request body dto.RenameTeamRequest true "Старое и новое имя"
+// @Success 200 {object} object{team=models.Team} "Переименованная команда"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос или имя занято"
+// @Failure 404 {object} errors.ErrorResponse "Команда не найдена"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /team/rename [post]
+func (h *Handler) RenameTeam(c echo.Context) error {
+	var req dto.RenameTeamRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.TeamName == "" || req.NewTeamName == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "team_name and new_team_name are required"))
+	}
+
+	team, err := h.Service.RenameTeam(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrTeamExists):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeTeamExists, "new_team_name already exists"))
+		case errors.Is(err, errors.ErrNotFound):
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "Team not found"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to rename team"))
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"team": team,
+	})
+}
+
+// DeleteTeam удаляет команду вместе с участниками
+// @Summary Удалить команду
+// @Description Удаляет команду и её участников. Открытые PR участников обрабатываются по open_pr_policy, как в PATCH /team.
+// @Description Смерженные и закрытые PR участников остаются без автора
+// @Tags Teams
+// @Accept json
+// @Produce json
+// @Param team_name query string true "Имя команды" example:"backend"
+// @Param open_pr_policy query string false "reject (по умолчанию), reassign или close" example:"close"
+// @Param new_author_id query string false "Новый автор PR для политики reassign" example:"u5"
+// @Success 200 {object} dto.DeleteTeamResponse "Команда удалена"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос или политика"
+// @Failure 404 {object} errors.ErrorResponse "Команда не найдена"
+// @Failure 409 {object} errors.ErrorResponse "У участников есть открытые PR"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /team [delete]
+func (h *Handler) DeleteTeam(c echo.Context) error {
+	req := dto.DeleteTeamRequest{
+		TeamName:     c.QueryParam("team_name"),
+		OpenPRPolicy: c.QueryParam("open_pr_policy"),
+		NewAuthorID:  c.QueryParam("new_author_id"),
+	}
+	if req.TeamName == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "team_name is required"))
+	}
+
+	result, err := h.Service.DeleteTeam(c.Request().Context(), req)
+	if err != nil {
+		return h.userRemovalError(c, err, "Team not found", "Failed to delete team")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// userRemovalError переводит ошибки удаления пользователей с открытыми PR в HTTP-ответ
+func (h *Handler) userRemovalError(c echo.Context, err error, notFoundMessage, internalMessage string) error {
+	var openPRs *errors.OpenPRsError
+	switch {
+	case errors.As(err, &openPRs):
+		return c.JSON(http.StatusConflict, errors.NewErrorResponseWithDetails(errors.CodeHasOpenPRs, "users have open pull requests, pass open_pr_policy reassign or close", openPRs.PRIDs))
+	case errors.Is(err, errors.ErrInvalidReviewPolicy):
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidPolicy, "review_policy must be reassign or keep"))
+	case errors.Is(err, errors.ErrInvalidPolicy):
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidPolicy, "open_pr_policy must be reject, reassign or close, reassign requires new_author_id outside of removed users"))
+	case errors.Is(err, errors.ErrNotFound):
+		return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, notFoundMessage))
+	default:
+		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", internalMessage))
+	}
+}
@@ -4,10 +4,163 @@ import (
 	"net/http"
 	"pr_task/internal/dto"
 	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
 
+// GetUser получает пользователя
+// @Summary Получить пользователя
+// @Description Возвращает пользователя по идентификатору
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param user_id query string true "Идентификатор пользователя" example:"u1"
+// @Success 200 {object} object{user=models.User} "Пользователь"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} errors.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/get [get]
+func (h *Handler) GetUser(c echo.Context) error {
+	userID := c.QueryParam("user_id")
+	if userID == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "user_id is required"))
+	}
+
+	user, err := h.Service.GetUser(c.Request().Context(), userID)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "User not found"))
+		}
+		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to get user"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"user": user,
+	})
+}
+
+// ListUsers ищет пользователей
+// @Summary Поиск пользователей
+// @Description Возвращает пользователей по возрастанию user_id с фильтрами по команде, активности и подстроке имени.
+// @Description Следующая страница запрашивается с cursor из next_cursor предыдущего ответа
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param team_name query string false "Команда" example:"backend"
+// @Param is_active query bool false "Флаг активности" example:"true"
+// @Param username query string false "Подстрока имени без учёта регистра" example:"ali"
+// @Param tag query string false "Тег навыка" example:"go"
+// @Param cursor query string false "user_id, после которого начинается страница" example:"u20"
+// @Param limit query int false "Размер страницы (1..100, по умолчанию 20)" example:"20"
+// @Success 200 {object} dto.UserListResponse "Страница пользователей"
+// @Failure 400 {object} errors.ErrorResponse "Неверные параметры"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users [get]
+func (h *Handler) ListUsers(c echo.Context) error {
+	filter := models.UserFilter{
+		TeamName: c.QueryParam("team_name"),
+		Username: c.QueryParam("username"),
+		Tag:      strings.ToLower(c.QueryParam("tag")),
+		Cursor:   c.QueryParam("cursor"),
+		Limit:    20,
+	}
+	if value := c.QueryParam("is_active"); value != "" {
+		isActive, err := strconv.ParseBool(value)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "is_active must be true or false"))
+		}
+		filter.IsActive = &isActive
+	}
+	if value := c.QueryParam("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 100 {
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "limit must be between 1 and 100"))
+		}
+		filter.Limit = n
+	}
+
+	result, err := h.Service.ListUsers(c.Request().Context(), filter)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to list users"))
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// UpdateUser меняет имя пользователя
+// @Summary Изменить имя пользователя
+// @Description Обновляет username пользователя
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateUserRequest true "Пользователь и новое имя"
+// @Success 200 {object} object{user=models.User} "Обновлённый пользователь"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} errors.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users [patch]
+func (h *Handler) UpdateUser(c echo.Context) error {
+	var req dto.UpdateUserRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.UserID == "" || req.Username == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "user_id and username are required"))
+	}
+
+	user, err := h.Service.UpdateUser(c.Request().Context(), req)
+	if err != nil {
+		if errors.Is(err, errors.ErrNotFound) {
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "User not found"))
+		}
+		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to update user"))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"user": user,
+	})
+}
+
+// DeleteUser удаляет пользователя
+// @Summary Удалить пользователя
+// @Description Открытые PR и черновики пользователя запрещают удаление (HAS_OPEN_PRS), если open_pr_policy не reassign
+// @Description (PR передаются new_author_id) или close (PR закрываются). Открытые ревью пользователя передаются участникам команды автора PR.
+// @Description Смерженные и закрытые PR пользователя остаются без автора
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param user_id query string true "Идентификатор пользователя" example:"u3"
+// @Param open_pr_policy query string false "reject (по умолчанию), reassign или close" example:"close"
+// @Param new_author_id query string false "Новый автор PR для политики reassign" example:"u1"
+// @Success 200 {object} dto.DeleteUserResponse "Пользователь удалён"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос или политика"
+// @Failure 404 {object} errors.ErrorResponse "Пользователь не найден"
+// @Failure 409 {object} errors.ErrorResponse "У пользователя есть открытые PR"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users [delete]
+func (h *Handler) DeleteUser(c echo.Context) error {
+	req := dto.DeleteUserRequest{
+		UserID:       c.QueryParam("user_id"),
+		OpenPRPolicy: c.QueryParam("open_pr_policy"),
+		NewAuthorID:  c.QueryParam("new_author_id"),
+	}
+	if req.UserID == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "user_id is required"))
+	}
+
+	result, err := h.Service.DeleteUser(c.Request().Context(), req)
+	if err != nil {
+		return h.userRemovalError(c, err, "User not found", "Failed to delete user")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
 // SetUserActive устанавливает флаг активности пользователя
 // @Summary Установить флаг активности пользователя
 // @Description Обновляет активность пользователя
@@ -15,7 +168,7 @@ import (
 // @Accept json
 // @Produce json
 // @Param request body dto.SetUserActiveRequest true "Данные пользователя"
-// @Success 200 {object} map[string]interface{} "Обновлённый пользователь"
+// @Success 200 {object} object{user=models.User} "Обновлённый пользователь"
 // @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
 // @Failure 404 {object} errors.ErrorResponse "Пользователь не найден"
 // @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
@@ -44,6 +197,143 @@ func (h *Handler) SetUserActive(c echo.Context) error {
 	})
 }
 
+// SetUserMaxOpenReviews задаёт личный предел открытых ревью пользователя
+// @Summary Задать предел открытых ревью пользователя
+// @Description Участник, у которого открытых ревью не меньше предела, не выбирается ревьювером. 0 возвращает предел команды по умолчанию
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body dto.SetMaxOpenReviewsRequest true "Пользователь и предел"
+// @Success 200 {object} object{user=models.User} "Обновлённый пользователь"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
+// @Failure 404 {object} errors.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/setMaxOpenReviews [post]
+func (h *Handler) SetUserMaxOpenReviews(c echo.Context) error {
+	var req dto.SetMaxOpenReviewsRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.UserID == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "user_id is required"))
+	}
+
+	user, err := h.Service.SetUserMaxOpenReviews(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrInvalidSettings):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidSettings, "max_open_reviews must not be negative"))
+		case errors.Is(err, errors.ErrNotFound):
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "User not found"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to update user"))
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"user": user,
+	})
+}
+
+// SetUserTags заменяет теги навыков пользователя
+// @Summary Задать теги навыков пользователя
+// @Description Теги приводятся к нижнему регистру, повторы убираются. Пустой список удаляет все теги
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body dto.SetUserTagsRequest true "Пользователь и теги"
+// @Success 200 {object} object{user=models.User} "Обновлённый пользователь"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос или теги"
+// @Failure 404 {object} errors.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/setTags [post]
+func (h *Handler) SetUserTags(c echo.Context) error {
+	var req dto.SetUserTagsRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.UserID == "" || req.Tags == nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "user_id and tags are required"))
+	}
+
+	user, err := h.Service.SetUserTags(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrInvalidTags):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidTags, err.Error()))
+		case errors.Is(err, errors.ErrNotFound):
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "User not found"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to update user"))
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"user": user,
+	})
+}
+
+// ListTags возвращает теги навыков
+// @Summary Список тегов навыков
+// @Description Все теги, назначенные пользователям, по алфавиту и число пользователей с каждым
+// @Tags Users
+// @Produce json
+// @Success 200 {object} dto.TagListResponse "Теги"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /tags [get]
+func (h *Handler) ListTags(c echo.Context) error {
+	result, err := h.Service.ListTags(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to list tags"))
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// MoveUser переводит пользователя в другую команду
+// @Summary Перевести пользователя в другую команду
+// @Description С review_policy=reassign (по умолчанию) каждое открытое ревью пользователя передаётся участнику прежней команды по её стратегии,
+// @Description все замены возвращаются в reassignments. PR без подходящей замены перечислены в not_reassigned_prs, пользователь остаётся в них ревьювером.
+// @Description С review_policy=keep открытые ревью остаются за пользователем
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body dto.MoveUserRequest true "Пользователь и новая команда"
+// @Success 200 {object} dto.MoveUserResponse "Пользователь переведён"
+// @Failure 400 {object} errors.ErrorResponse "Неверный запрос или политика"
+// @Failure 404 {object} errors.ErrorResponse "Пользователь или команда не найдены"
+// @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /users/move [post]
+func (h *Handler) MoveUser(c echo.Context) error {
+	var req dto.MoveUserRequest
+
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "Invalid request body"))
+	}
+
+	if req.UserID == "" || req.TeamName == "" {
+		return c.JSON(http.StatusBadRequest, errors.NewErrorResponse("INVALID_REQUEST", "user_id and team_name are required"))
+	}
+
+	result, err := h.Service.MoveUser(c.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errors.ErrInvalidReviewPolicy):
+			return c.JSON(http.StatusBadRequest, errors.NewErrorResponse(errors.CodeInvalidPolicy, "review_policy must be reassign or keep"))
+		case errors.Is(err, errors.ErrNotFound):
+			return c.JSON(http.StatusNotFound, errors.NewErrorResponse(errors.CodeNotFound, "User or team not found"))
+		default:
+			return c.JSON(http.StatusInternalServerError, errors.NewErrorResponse("INTERNAL_ERROR", "Failed to move user"))
+		}
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
 // GetUserReviews получает PR'ы, где пользователь назначен ревьювером
 // @Summary Получить PR'ы, где пользователь назначен ревьювером
 // @Description Возвращает список PR для ревью пользователя
@@ -51,7 +341,7 @@ func (h *Handler) SetUserActive(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param user_id query string true "Идентификатор пользователя" example:"u1"
-// @Success 200 {object} models.UserReviewResponse "Список PR'ов пользователя"
+// @Success 200 {object} dto.UserReviewResponse "Список PR'ов пользователя"
 // @Failure 400 {object} errors.ErrorResponse "Неверный запрос"
 // @Failure 404 {object} errors.ErrorResponse "Пользователь не найден"
 // @Failure 500 {object} errors.ErrorResponse "Внутренняя ошибка сервера"
@@ -0,0 +1,42 @@
+-- Базовая схема. Все миграции написаны идемпотентно, чтобы базы, созданные до появления schema_migrations
+-- из init/init.sql и скриптов migrations/, доводились до актуальной схемы без ошибок
+DO $$ BEGIN
+    CREATE TYPE pr_status AS ENUM ('OPEN', 'MERGED');
+EXCEPTION
+    WHEN duplicate_object THEN null;
+END $$;
+
+CREATE TABLE IF NOT EXISTS team (
+    team_name TEXT PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS "user" (
+    user_id   TEXT PRIMARY KEY,
+    username  TEXT NOT NULL,
+    team_name TEXT NOT NULL REFERENCES team(team_name) ON DELETE CASCADE,
+    is_active BOOLEAN NOT NULL DEFAULT true
+);
+
+CREATE TABLE IF NOT EXISTS pull_request (
+    pull_request_id    TEXT PRIMARY KEY,
+    pull_request_name  TEXT NOT NULL,
+    author_id          TEXT NOT NULL REFERENCES "user"(user_id),
+    status             pr_status NOT NULL DEFAULT 'OPEN',
+    assigned_reviewers TEXT[] NOT NULL DEFAULT '{}',
+    created_at         TIMESTAMPTZ DEFAULT NOW(),
+    merged_at          TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_team_name ON "user"(team_name);
+CREATE INDEX IF NOT EXISTS idx_pr_author_id ON pull_request(author_id);
+CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_request(status);
+
+-- В базах, созданных поздними версиями init.sql, массива assigned_reviewers уже нет
+DO $$ BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'pull_request' AND column_name = 'assigned_reviewers'
+    ) THEN
+        CREATE INDEX IF NOT EXISTS idx_pr_reviewers ON pull_request USING GIN (assigned_reviewers);
+    END IF;
+END $$;
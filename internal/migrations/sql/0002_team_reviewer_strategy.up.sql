@@ -0,0 +1 @@
+ALTER TABLE team ADD COLUMN IF NOT EXISTS reviewer_strategy TEXT NOT NULL DEFAULT 'least_loaded';
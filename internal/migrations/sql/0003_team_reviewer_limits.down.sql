@@ -0,0 +1,3 @@
+ALTER TABLE team DROP CONSTRAINT IF EXISTS team_reviewer_limits_check;
+ALTER TABLE team DROP COLUMN IF EXISTS max_reviewers;
+ALTER TABLE team DROP COLUMN IF EXISTS min_reviewers;
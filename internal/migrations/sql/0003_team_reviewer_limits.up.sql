@@ -0,0 +1,4 @@
+ALTER TABLE team ADD COLUMN IF NOT EXISTS min_reviewers INT NOT NULL DEFAULT 0 CHECK (min_reviewers >= 0);
+ALTER TABLE team ADD COLUMN IF NOT EXISTS max_reviewers INT NOT NULL DEFAULT 2 CHECK (max_reviewers >= 1);
+ALTER TABLE team DROP CONSTRAINT IF EXISTS team_reviewer_limits_check;
+ALTER TABLE team ADD CONSTRAINT team_reviewer_limits_check CHECK (min_reviewers <= max_reviewers);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS team_rotation (
+    team_name    TEXT PRIMARY KEY REFERENCES team(team_name) ON DELETE CASCADE,
+    last_user_id TEXT NOT NULL DEFAULT '',
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
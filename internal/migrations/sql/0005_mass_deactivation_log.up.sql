@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS mass_deactivation (
+    operation_id         TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    team_name            TEXT NOT NULL REFERENCES team(team_name) ON DELETE CASCADE,
+    deactivated_user_ids TEXT[] NOT NULL DEFAULT '{}',
+    pr_changes           JSONB NOT NULL DEFAULT '[]',
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    undone_at            TIMESTAMPTZ
+);
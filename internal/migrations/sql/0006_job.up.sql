@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS job (
+    job_id       TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    job_type     TEXT NOT NULL,
+    status       TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'RUNNING', 'SUCCEEDED', 'FAILED')),
+    payload      JSONB NOT NULL DEFAULT '{}',
+    progress     INT NOT NULL DEFAULT 0,
+    total        INT NOT NULL DEFAULT 0,
+    result       JSONB,
+    error        TEXT NOT NULL DEFAULT '',
+    attempts     INT NOT NULL DEFAULT 0,
+    locked_until TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at   TIMESTAMPTZ,
+    finished_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_status ON job(status, created_at);
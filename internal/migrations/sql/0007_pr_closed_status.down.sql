@@ -0,0 +1,3 @@
+-- Значение из enum удалить нельзя: закрытые PR возвращаются в OPEN, а CLOSED остаётся в pr_status неиспользуемым
+UPDATE pull_request SET status = 'OPEN' WHERE status = 'CLOSED';
+ALTER TABLE pull_request DROP COLUMN IF EXISTS closed_at;
@@ -0,0 +1,3 @@
+ALTER TYPE pr_status ADD VALUE IF NOT EXISTS 'CLOSED';
+
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ;
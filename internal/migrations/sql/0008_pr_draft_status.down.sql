@@ -0,0 +1,2 @@
+-- Значение из enum удалить нельзя: черновики становятся открытыми PR, а DRAFT остаётся в pr_status неиспользуемым
+UPDATE pull_request SET status = 'OPEN' WHERE status = 'DRAFT';
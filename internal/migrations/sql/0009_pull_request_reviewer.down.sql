@@ -0,0 +1,12 @@
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS assigned_reviewers TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE pull_request pr SET assigned_reviewers = reviewers.user_ids
+FROM (
+    SELECT pull_request_id, array_agg(user_id ORDER BY position) AS user_ids
+    FROM pull_request_reviewer
+    GROUP BY pull_request_id
+) reviewers
+WHERE reviewers.pull_request_id = pr.pull_request_id;
+
+CREATE INDEX IF NOT EXISTS idx_pr_reviewers ON pull_request USING GIN (assigned_reviewers);
+DROP TABLE IF EXISTS pull_request_reviewer;
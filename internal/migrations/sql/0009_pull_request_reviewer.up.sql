@@ -0,0 +1,28 @@
+-- Таблица могла быть уже создана и переименована в pr_reviewer скриптами, применёнными вручную до schema_migrations
+DO $$ BEGIN
+    IF to_regclass('pr_reviewer') IS NULL THEN
+        CREATE TABLE IF NOT EXISTS pull_request_reviewer (
+            pull_request_id TEXT NOT NULL REFERENCES pull_request(pull_request_id) ON DELETE CASCADE,
+            user_id         TEXT NOT NULL,
+            position        INT NOT NULL DEFAULT 0,
+            state           TEXT NOT NULL DEFAULT 'PENDING' CHECK (state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+            assigned_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+            reviewed_at     TIMESTAMPTZ,
+            PRIMARY KEY (pull_request_id, user_id)
+        );
+        CREATE INDEX IF NOT EXISTS idx_pr_reviewer_user_id ON pull_request_reviewer(user_id);
+    END IF;
+
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'pull_request' AND column_name = 'assigned_reviewers'
+    ) THEN
+        INSERT INTO pull_request_reviewer (pull_request_id, user_id, position, assigned_at)
+        SELECT pr.pull_request_id, reviewer.user_id, reviewer.position, COALESCE(pr.created_at, NOW())
+        FROM pull_request pr, unnest(pr.assigned_reviewers) WITH ORDINALITY AS reviewer(user_id, position)
+        ON CONFLICT (pull_request_id, user_id) DO NOTHING;
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS idx_pr_reviewers;
+ALTER TABLE pull_request DROP COLUMN IF EXISTS assigned_reviewers;
@@ -0,0 +1,9 @@
+ALTER TABLE pull_request DROP COLUMN IF EXISTS bypassed_conditions;
+ALTER TABLE pull_request DROP COLUMN IF EXISTS force_merged;
+ALTER TABLE pull_request DROP COLUMN IF EXISTS merged_by;
+
+ALTER TABLE "user" DROP COLUMN IF EXISTS is_lead;
+
+ALTER TABLE team DROP COLUMN IF EXISTS require_lead_approval;
+ALTER TABLE team DROP COLUMN IF EXISTS block_on_changes_requested;
+ALTER TABLE team DROP COLUMN IF EXISTS required_approvals;
@@ -0,0 +1,9 @@
+ALTER TABLE team ADD COLUMN IF NOT EXISTS required_approvals INT NOT NULL DEFAULT 0 CHECK (required_approvals >= 0);
+ALTER TABLE team ADD COLUMN IF NOT EXISTS block_on_changes_requested BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE team ADD COLUMN IF NOT EXISTS require_lead_approval BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS is_lead BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS merged_by TEXT NOT NULL DEFAULT '';
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS force_merged BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS bypassed_conditions TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,5 @@
+ALTER TABLE pr_reviewer DROP CONSTRAINT IF EXISTS pr_reviewer_user_id_fkey;
+ALTER TABLE pr_reviewer DROP COLUMN IF EXISTS assigned_by;
+
+ALTER INDEX IF EXISTS pr_reviewer_pkey RENAME TO pull_request_reviewer_pkey;
+ALTER TABLE IF EXISTS pr_reviewer RENAME TO pull_request_reviewer;
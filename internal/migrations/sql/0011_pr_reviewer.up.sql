@@ -0,0 +1,12 @@
+ALTER TABLE IF EXISTS pull_request_reviewer RENAME TO pr_reviewer;
+ALTER INDEX IF EXISTS pull_request_reviewer_pkey RENAME TO pr_reviewer_pkey;
+
+ALTER TABLE pr_reviewer ADD COLUMN IF NOT EXISTS assigned_by TEXT NOT NULL DEFAULT '';
+UPDATE pr_reviewer SET assigned_by = 'migration' WHERE assigned_by = '';
+
+-- Назначения удалённых пользователей не дают создать внешний ключ
+DELETE FROM pr_reviewer r WHERE NOT EXISTS (SELECT 1 FROM "user" u WHERE u.user_id = r.user_id);
+
+ALTER TABLE pr_reviewer DROP CONSTRAINT IF EXISTS pr_reviewer_user_id_fkey;
+ALTER TABLE pr_reviewer ADD CONSTRAINT pr_reviewer_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES "user"(user_id) ON UPDATE CASCADE ON DELETE CASCADE;
@@ -0,0 +1,18 @@
+-- PR без автора нельзя сохранить при NOT NULL, поэтому они удаляются
+DELETE FROM pull_request WHERE author_id IS NULL;
+ALTER TABLE pull_request DROP CONSTRAINT IF EXISTS pull_request_author_id_fkey;
+ALTER TABLE pull_request ADD CONSTRAINT pull_request_author_id_fkey
+    FOREIGN KEY (author_id) REFERENCES "user"(user_id);
+ALTER TABLE pull_request ALTER COLUMN author_id SET NOT NULL;
+
+ALTER TABLE mass_deactivation DROP CONSTRAINT IF EXISTS mass_deactivation_team_name_fkey;
+ALTER TABLE mass_deactivation ADD CONSTRAINT mass_deactivation_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES team(team_name) ON DELETE CASCADE;
+
+ALTER TABLE team_rotation DROP CONSTRAINT IF EXISTS team_rotation_team_name_fkey;
+ALTER TABLE team_rotation ADD CONSTRAINT team_rotation_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES team(team_name) ON DELETE CASCADE;
+
+ALTER TABLE "user" DROP CONSTRAINT IF EXISTS user_team_name_fkey;
+ALTER TABLE "user" ADD CONSTRAINT user_team_name_fkey
+    FOREIGN KEY (team_name) REFERENCES team(team_name) ON DELETE CASCADE;
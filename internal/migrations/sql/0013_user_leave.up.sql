@@ -0,0 +1,15 @@
+-- Периоды отсутствия пользователей: в эти даты они не выбираются ревьюверами.
+-- reassigned_at отмечает, что открытые ревью уже переданы при начале отпуска с auto_reassign
+CREATE TABLE IF NOT EXISTS user_leave (
+    leave_id      TEXT PRIMARY KEY DEFAULT gen_random_uuid()::text,
+    user_id       TEXT NOT NULL REFERENCES "user"(user_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    starts_on     DATE NOT NULL,
+    ends_on       DATE NOT NULL,
+    reason        TEXT NOT NULL DEFAULT '',
+    auto_reassign BOOLEAN NOT NULL DEFAULT false,
+    reassigned_at TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_on >= starts_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_leave_user ON user_leave(user_id, starts_on);
@@ -0,0 +1,2 @@
+ALTER TABLE "user" DROP COLUMN IF EXISTS max_open_reviews;
+ALTER TABLE team DROP COLUMN IF EXISTS default_max_open_reviews;
@@ -0,0 +1,3 @@
+-- Предел открытых ревью: личный у пользователя, иначе по умолчанию команды. NULL - без ограничения
+ALTER TABLE team ADD COLUMN IF NOT EXISTS default_max_open_reviews INT CHECK (default_max_open_reviews >= 1);
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS max_open_reviews INT CHECK (max_open_reviews >= 1);
@@ -0,0 +1,12 @@
+-- Запасные команды по порядку: из них выбираются ревьюверы, когда своя команда не может дать нужное число
+CREATE TABLE IF NOT EXISTS team_fallback (
+    team_name          TEXT NOT NULL REFERENCES team(team_name) ON UPDATE CASCADE ON DELETE CASCADE,
+    fallback_team_name TEXT NOT NULL REFERENCES team(team_name) ON UPDATE CASCADE ON DELETE CASCADE,
+    position           INT NOT NULL,
+    PRIMARY KEY (team_name, fallback_team_name),
+    CHECK (team_name <> fallback_team_name)
+);
+
+-- Запасная команда, из которой назначен ревьювер; NULL - ревьювер из своей команды
+ALTER TABLE pr_reviewer ADD COLUMN IF NOT EXISTS fallback_team TEXT
+    REFERENCES team(team_name) ON UPDATE CASCADE ON DELETE SET NULL;
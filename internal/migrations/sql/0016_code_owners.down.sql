@@ -0,0 +1,3 @@
+ALTER TABLE pull_request DROP COLUMN IF EXISTS changed_files;
+DROP TABLE IF EXISTS code_owner;
+DROP TABLE IF EXISTS code_owner_rule;
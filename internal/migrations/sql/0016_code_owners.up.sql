@@ -0,0 +1,19 @@
+-- Реестр владельцев кода в стиле CODEOWNERS: правила по порядку, для файла действует последнее подходящее
+CREATE TABLE IF NOT EXISTS code_owner_rule (
+    rule_id  BIGSERIAL PRIMARY KEY,
+    position INT NOT NULL,
+    pattern  TEXT NOT NULL
+);
+
+-- Владелец правила - пользователь или команда
+CREATE TABLE IF NOT EXISTS code_owner (
+    rule_id   BIGINT NOT NULL REFERENCES code_owner_rule(rule_id) ON DELETE CASCADE,
+    user_id   TEXT REFERENCES "user"(user_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    team_name TEXT REFERENCES team(team_name) ON UPDATE CASCADE ON DELETE CASCADE,
+    CHECK ((user_id IS NULL) <> (team_name IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_code_owner_rule ON code_owner(rule_id);
+
+-- Изменённые файлы PR, по ним ревьюверы выбираются и при переводе черновика в OPEN
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS changed_files TEXT[] NOT NULL DEFAULT '{}';
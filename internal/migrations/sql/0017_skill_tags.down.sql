@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_user_tags;
+ALTER TABLE pull_request DROP COLUMN IF EXISTS required_tags;
+ALTER TABLE "user" DROP COLUMN IF EXISTS tags;
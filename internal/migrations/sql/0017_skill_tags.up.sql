@@ -0,0 +1,5 @@
+-- Теги навыков пользователей и требуемые теги PR: при выборе ревьюверов сначала учитывается совпадение тегов
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE pull_request ADD COLUMN IF NOT EXISTS required_tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_user_tags ON "user" USING GIN (tags);
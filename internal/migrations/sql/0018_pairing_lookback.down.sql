@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pull_request_author_created;
+ALTER TABLE team DROP COLUMN IF EXISTS pairing_lookback;
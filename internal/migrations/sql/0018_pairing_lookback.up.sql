@@ -0,0 +1,4 @@
+-- Сколько последних PR автора учитывать, чтобы не назначать ему одних и тех же ревьюверов. 0 - не учитывать
+ALTER TABLE team ADD COLUMN IF NOT EXISTS pairing_lookback INT NOT NULL DEFAULT 0 CHECK (pairing_lookback BETWEEN 0 AND 100);
+
+CREATE INDEX IF NOT EXISTS idx_pull_request_author_created ON pull_request(author_id, created_at DESC);
@@ -0,0 +1,2 @@
+-- Объяснение автоматического выбора ревьювера: стратегия, размер пула кандидатов, нагрузка и исключения
+ALTER TABLE pr_reviewer ADD COLUMN IF NOT EXISTS explanation JSONB;
@@ -0,0 +1 @@
+ALTER TABLE user_leave DROP CONSTRAINT IF EXISTS user_leave_no_overlap;
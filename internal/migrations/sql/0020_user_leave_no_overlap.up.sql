@@ -0,0 +1,11 @@
+-- Пересекающиеся периоды отсутствия одного пользователя запрещены на уровне БД,
+-- чтобы параллельные запросы не сохранили их оба после проверки в сервисе
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'user_leave_no_overlap') THEN
+        ALTER TABLE user_leave ADD CONSTRAINT user_leave_no_overlap
+            EXCLUDE USING gist (user_id WITH =, daterange(starts_on, ends_on, '[]') WITH &&);
+    END IF;
+END $$;
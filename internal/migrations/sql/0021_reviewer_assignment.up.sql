@@ -0,0 +1,16 @@
+-- Журнал назначений ревьюверов только дополняется: снятие ревьювера с PR запись не удаляет
+CREATE TABLE IF NOT EXISTS reviewer_assignment (
+    assignment_id   BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL REFERENCES pull_request(pull_request_id) ON DELETE CASCADE,
+    user_id         TEXT NOT NULL REFERENCES "user"(user_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    assigned_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    assigned_by     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_reviewer_assignment_pull_request_id ON reviewer_assignment(pull_request_id);
+
+-- Назначения, сделанные до появления журнала, известны только по текущим ревьюверам
+INSERT INTO reviewer_assignment (pull_request_id, user_id, assigned_at, assigned_by)
+SELECT r.pull_request_id, r.user_id, r.assigned_at, r.assigned_by
+FROM pr_reviewer r
+WHERE NOT EXISTS (SELECT 1 FROM reviewer_assignment a WHERE a.pull_request_id = r.pull_request_id)
+ORDER BY r.assigned_at, r.pull_request_id, r.position;
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type Team struct {
 	TeamName string       `json:"team_name"`
 	Members  []TeamMember `json:"members"`
@@ -9,6 +14,9 @@ type TeamMember struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	IsActive bool   `json:"is_active"`
+	IsLead   bool   `json:"is_lead"`
+	// Tags - теги навыков. Без тегов у существующего пользователя сохраняются прежние
+	Tags []string `json:"tags,omitempty"`
 }
 
 type User struct {
@@ -16,23 +24,203 @@ type User struct {
 	Username string `json:"username"`
 	TeamName string `json:"team_name"`
 	IsActive bool   `json:"is_active"`
+	IsLead   bool   `json:"is_lead"`
+	// Личный предел открытых ревью, без него действует предел команды по умолчанию
+	MaxOpenReviews *int     `json:"max_open_reviews,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+}
+
+// UserAvailability - пользователь и его отсутствие сегодня по календарю
+type UserAvailability struct {
+	User
+	OnLeave bool
+}
+
+// UserFilter отбирает пользователей для /users. Пустые поля не фильтруют, Cursor - user_id, после которого начинается страница
+type UserFilter struct {
+	TeamName string
+	IsActive *bool
+	Username string
+	Tag      string
+	Cursor   string
+	Limit    int
+}
+
+// UserLeave - период отсутствия пользователя, даты в формате YYYY-MM-DD включительно.
+// С AutoReassign открытые ревью пользователя передаются его команде, когда период начинается
+type UserLeave struct {
+	LeaveID      string     `json:"leave_id"`
+	UserID       string     `json:"user_id"`
+	StartsOn     string     `json:"starts_on"`
+	EndsOn       string     `json:"ends_on"`
+	Reason       string     `json:"reason"`
+	AutoReassign bool       `json:"auto_reassign"`
+	ReassignedAt *time.Time `json:"reassigned_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// CodeOwnerRule - правило реестра владельцев кода: файлы по шаблону Pattern (синтаксис CODEOWNERS) принадлежат
+// пользователям Users и участникам команд Teams
+type CodeOwnerRule struct {
+	Pattern string   `json:"pattern"`
+	Users   []string `json:"users"`
+	Teams   []string `json:"teams"`
+}
+
+type TeamSettings struct {
+	TeamName         string `json:"team_name"`
+	ReviewerStrategy string `json:"reviewer_strategy"`
+	MinReviewers     int    `json:"min_reviewers"`
+	MaxReviewers     int    `json:"max_reviewers"`
+	// Политика мержа: минимум одобрений, запрет при запрошенных изменениях, обязательное одобрение тимлида
+	RequiredApprovals       int  `json:"required_approvals"`
+	BlockOnChangesRequested bool `json:"block_on_changes_requested"`
+	RequireLeadApproval     bool `json:"require_lead_approval"`
+	// Предел открытых ревью участника по умолчанию, nil - без ограничения
+	DefaultMaxOpenReviews *int `json:"default_max_open_reviews,omitempty"`
+	// Запасные команды по порядку, из них добираются ревьюверы, которых не хватило в своей команде
+	FallbackTeams []string `json:"fallback_teams"`
+	// PairingLookback - сколько последних PR автора учитывать: их ревьюверы выбираются в последнюю очередь, 0 - не учитывать
+	PairingLookback int `json:"pairing_lookback"`
+}
+
+type ReviewerCandidate struct {
+	UserID      string `json:"user_id"`
+	OpenReviews int    `json:"open_reviews"`
+	// MaxOpenReviews - предел открытых ревью кандидата, 0 - без ограничения
+	MaxOpenReviews int      `json:"max_open_reviews,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	// RecentReviews - в скольких из последних PR автора кандидат был ревьювером
+	RecentReviews int `json:"recent_reviews,omitempty"`
+}
+
+// Причины, по которым участник не рассматривался при выборе ревьювера
+const (
+	ExclusionAuthor          = "author"
+	ExclusionAlreadyAssigned = "already_assigned"
+	ExclusionInactive        = "inactive"
+	ExclusionOnLeave         = "on_leave"
+	ExclusionAtCapacity      = "at_capacity"
+)
+
+// AssignmentExplanation объясняет, почему назначен ревьювер: по какой стратегии и из какой команды он выбран,
+// из скольких кандидатов, с какой нагрузкой и совпадениями, и кто по каким причинам не рассматривался
+type AssignmentExplanation struct {
+	Strategy string `json:"strategy"`
+	TeamName string `json:"team_name"`
+	// CodeOwnerPattern - область реестра владельцев кода, за которую назначен ревьювер
+	CodeOwnerPattern string `json:"code_owner_pattern,omitempty"`
+	// CandidatePool - сколько кандидатов осталось после исключений
+	CandidatePool  int `json:"candidate_pool"`
+	OpenReviews    int `json:"open_reviews"`
+	MaxOpenReviews int `json:"max_open_reviews,omitempty"`
+	// TagOverlap и RecentReviews - совпавшие требуемые теги и недавние ревью PR автора, по ним кандидаты упорядочиваются до стратегии
+	TagOverlap    int `json:"tag_overlap"`
+	RecentReviews int `json:"recent_reviews"`
+	// Exclusions - исключённые участники по причинам (Exclusion*)
+	Exclusions map[string][]string `json:"exclusions,omitempty"`
+}
+
+type PRReviewer struct {
+	UserID     string     `json:"user_id"`
+	State      string     `json:"state"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	AssignedBy string     `json:"assigned_by"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	// FallbackTeam - запасная команда, из которой назначен ревьювер
+	FallbackTeam string `json:"fallback_team,omitempty"`
+}
+
+// ReviewAssignment - назначение ревьювера на PR автора, запись истории назначений
+type ReviewAssignment struct {
+	PRID       string    `json:"pr_id"`
+	AuthorID   string    `json:"author_id"`
+	ReviewerID string    `json:"reviewer_id"`
+	AssignedAt time.Time `json:"assigned_at"`
+	AssignedBy string    `json:"assigned_by"`
 }
 
 type OpenPRInfo struct {
 	PRID              string   `json:"pr_id"`
 	AuthorID          string   `json:"author_id"`
 	AssignedReviewers []string `json:"assigned_reviewers"`
+	// FallbackReviewers - ревьюверы из запасных команд и их команды
+	FallbackReviewers map[string]string `json:"fallback_reviewers,omitempty"`
+	RequiredTags      []string          `json:"required_tags,omitempty"`
 }
 
 type PRReviewersUpdate struct {
 	PRID      string   `json:"pr_id"`
 	Reviewers []string `json:"reviewers"`
+	// FallbackTeams - запасные команды новых ревьюверов, назначенных не из своей команды
+	FallbackTeams map[string]string `json:"fallback_teams,omitempty"`
+	// Explanations - объяснения выбора новых ревьюверов
+	Explanations map[string]*AssignmentExplanation `json:"explanations,omitempty"`
+}
+
+type PRReviewersChange struct {
+	PRID   string   `json:"pr_id"`
+	Before []string `json:"before"`
+	After  []string `json:"after"`
+	// FallbackTeams - запасные команды новых ревьюверов, назначенных не из своей команды
+	FallbackTeams map[string]string `json:"fallback_teams,omitempty"`
+	// Explanations - объяснения выбора новых ревьюверов
+	Explanations map[string]*AssignmentExplanation `json:"explanations,omitempty"`
+}
+
+// OpenPRHandoff описывает, что стало с открытыми PR удалённых пользователей
+type OpenPRHandoff struct {
+	ClosedPRs      []string            `json:"closed_prs,omitempty"`
+	TransferredPRs []string            `json:"transferred_prs,omitempty"`
+	PRChanges      []PRReviewersChange `json:"pr_changes,omitempty"`
+}
+
+// ReviewerReassignment - замена ревьювера в открытом PR при переводе пользователя в другую команду
+type ReviewerReassignment struct {
+	PRID          string `json:"pr_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id"`
+	FallbackTeam  string `json:"fallback_team,omitempty"`
+}
+
+// ReviewHandoff описывает, что стало с открытыми ревью переведённого пользователя.
+// NotReassignedPRs - PR, для которых в прежней команде не нашлось замены, пользователь остаётся в них ревьювером
+type ReviewHandoff struct {
+	Reassignments    []ReviewerReassignment `json:"reassignments,omitempty"`
+	NotReassignedPRs []string               `json:"not_reassigned_prs,omitempty"`
 }
 
 type MassDeactivationResult struct {
-	DeactivatedUsers int      `json:"deactivated_users"`
-	UpdatedPRs       int      `json:"updated_prs"`
-	FailedPRs        []string `json:"failed_prs,omitempty"`
+	DeactivatedUsers   int                 `json:"deactivated_users"`
+	DeactivatedUserIDs []string            `json:"deactivated_user_ids"`
+	OperationID        string              `json:"operation_id,omitempty"`
+	UpdatedPRs         int                 `json:"updated_prs"`
+	UnderstaffedPRs    []string            `json:"understaffed_prs,omitempty"`
+	PRChanges          []PRReviewersChange `json:"pr_changes,omitempty"`
+}
+
+type MassDeactivationOperation struct {
+	OperationID        string              `json:"operation_id"`
+	TeamName           string              `json:"team_name"`
+	DeactivatedUserIDs []string            `json:"deactivated_user_ids"`
+	PRChanges          []PRReviewersChange `json:"pr_changes"`
+	CreatedAt          time.Time           `json:"created_at"`
+	UndoneAt           *time.Time          `json:"undone_at,omitempty"`
+}
+
+type Job struct {
+	JobID      string          `json:"job_id"`
+	Type       string          `json:"type"`
+	Status     string          `json:"status"`
+	Payload    json.RawMessage `json:"payload" swaggertype:"object"`
+	Progress   int             `json:"progress"`
+	Total      int             `json:"total"`
+	Result     json.RawMessage `json:"result,omitempty" swaggertype:"object"`
+	Error      string          `json:"error,omitempty"`
+	Attempts   int             `json:"attempts"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
 }
 
 type UserReviewStats struct {
@@ -40,6 +228,9 @@ type UserReviewStats struct {
 	Username    string `json:"username"`
 	TeamName    string `json:"team_name"`
 	ReviewCount int    `json:"review_count"`
+	OpenReviews int    `json:"open_reviews"`
+	// Действующий предел открытых ревью: личный или команды по умолчанию, nil - без ограничения
+	MaxOpenReviews *int `json:"max_open_reviews,omitempty"`
 }
 
 type PRReviewStats struct {
@@ -54,6 +245,8 @@ type OverallStats struct {
 	TotalPRs        int     `json:"total_prs"`
 	OpenPRs         int     `json:"open_prs"`
 	MergedPRs       int     `json:"merged_prs"`
+	ClosedPRs       int     `json:"closed_prs"`
+	DraftPRs        int     `json:"draft_prs"`
 	TotalUsers      int     `json:"total_users"`
 	ActiveUsers     int     `json:"active_users"`
 	TotalReviews    int     `json:"total_reviews"`
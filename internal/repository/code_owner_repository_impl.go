@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	models "pr_task/internal/model"
+)
+
+func (r *PostgresRepository) GetCodeOwnerRules(ctx context.Context) ([]models.CodeOwnerRule, error) {
+	query := `
+		SELECT cr.rule_id, cr.pattern, co.user_id, co.team_name
+		FROM code_owner_rule cr
+		LEFT JOIN code_owner co ON co.rule_id = cr.rule_id
+		ORDER BY cr.position, co.user_id, co.team_name
+	`
+	rows, err := r.q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code owner rules: %v", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Println(err)
+		}
+	}(rows)
+
+	rules := []models.CodeOwnerRule{}
+	var lastRuleID int64
+	for rows.Next() {
+		var ruleID int64
+		var pattern string
+		var userID, teamName sql.NullString
+		if err := rows.Scan(&ruleID, &pattern, &userID, &teamName); err != nil {
+			return nil, fmt.Errorf("scan error: %v", err)
+		}
+
+		if len(rules) == 0 || ruleID != lastRuleID {
+			rules = append(rules, models.CodeOwnerRule{Pattern: pattern, Users: []string{}, Teams: []string{}})
+			lastRuleID = ruleID
+		}
+		rule := &rules[len(rules)-1]
+		if userID.Valid {
+			rule.Users = append(rule.Users, userID.String)
+		}
+		if teamName.Valid {
+			rule.Teams = append(rule.Teams, teamName.String)
+		}
+	}
+	return rules, rows.Err()
+}
+
+func (r *PostgresRepository) ReplaceCodeOwnerRules(ctx context.Context, rules []models.CodeOwnerRule) error {
+	return r.withTx(ctx, func(tx *PostgresRepository) error {
+		if _, err := tx.q.ExecContext(ctx, `DELETE FROM code_owner_rule`); err != nil {
+			return fmt.Errorf("failed to clear code owner rules: %v", err)
+		}
+
+		ruleQuery := `INSERT INTO code_owner_rule (position, pattern) VALUES ($1, $2) RETURNING rule_id`
+		userQuery := `INSERT INTO code_owner (rule_id, user_id) VALUES ($1, $2)`
+		teamQuery := `INSERT INTO code_owner (rule_id, team_name) VALUES ($1, $2)`
+		for i, rule := range rules {
+			var ruleID int64
+			if err := tx.q.QueryRowContext(ctx, ruleQuery, i+1, rule.Pattern).Scan(&ruleID); err != nil {
+				return fmt.Errorf("failed to create code owner rule %q: %v", rule.Pattern, err)
+			}
+			for _, userID := range rule.Users {
+				if _, err := tx.q.ExecContext(ctx, userQuery, ruleID, userID); err != nil {
+					return fmt.Errorf("failed to add owner %s to rule %q: %v", userID, rule.Pattern, err)
+				}
+			}
+			for _, teamName := range rule.Teams {
+				if _, err := tx.q.ExecContext(ctx, teamQuery, ruleID, teamName); err != nil {
+					return fmt.Errorf("failed to add owner team %s to rule %q: %v", teamName, rule.Pattern, err)
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,195 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	models "pr_task/internal/model"
+	"time"
+)
+
+const jobColumns = `job_id, job_type, status, payload, progress, total, result, error, attempts, created_at, started_at, finished_at`
+
+func scanJob(row interface{ Scan(dest ...any) error }) (*models.Job, error) {
+	var job models.Job
+	var payload, result []byte
+	err := row.Scan(
+		&job.JobID,
+		&job.Type,
+		&job.Status,
+		&payload,
+		&job.Progress,
+		&job.Total,
+		&result,
+		&job.Error,
+		&job.Attempts,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	job.Payload = payload
+	job.Result = result
+	return &job, nil
+}
+
+func (r *PostgresRepository) CreateJob(ctx context.Context, job *models.Job) error {
+	query := `
+		INSERT INTO job (job_type, payload)
+		VALUES ($1, $2)
+		RETURNING ` + jobColumns
+	created, err := scanJob(r.q.QueryRowContext(ctx, query, job.Type, []byte(job.Payload)))
+	if err != nil {
+		return fmt.Errorf("failed to create job: %v", err)
+	}
+	*job = *created
+	return nil
+}
+
+func (r *PostgresRepository) GetJob(ctx context.Context, jobID string) (*models.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM job WHERE job_id = $1`
+	job, err := scanJob(r.q.QueryRowContext(ctx, query, jobID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("job not found")
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// ClaimJob забирает самую старую ожидающую задачу либо задачу, чей исполнитель не продлил аренду
+// (например, сервис был перезапущен). Возвращает nil, если задач нет
+func (r *PostgresRepository) ClaimJob(ctx context.Context, lease time.Duration, maxAttempts int) (*models.Job, error) {
+	query := `
+		UPDATE job SET
+			status = 'RUNNING',
+			attempts = attempts + 1,
+			progress = 0,
+			locked_until = NOW() + make_interval(secs => $1),
+			started_at = NOW(),
+			updated_at = NOW()
+		WHERE job_id = (
+			SELECT job_id FROM job
+			WHERE attempts < $2
+			AND (status = 'PENDING' OR (status = 'RUNNING' AND locked_until < NOW()))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+	job, err := scanJob(r.q.QueryRowContext(ctx, query, lease.Seconds(), maxAttempts))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim job: %v", err)
+	}
+	return job, nil
+}
+
+func (r *PostgresRepository) UpdateJobProgress(ctx context.Context, jobID string, attempt, progress, total int, lease time.Duration) error {
+	query := `
+		UPDATE job SET
+			progress = $3,
+			total = $4,
+			locked_until = NOW() + make_interval(secs => $5),
+			updated_at = NOW()
+		WHERE job_id = $1 AND status = 'RUNNING' AND attempts = $2
+	`
+	result, err := r.q.ExecContext(ctx, query, jobID, attempt, progress, total, lease.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to update job progress: %v", err)
+	}
+	return checkJobLease(result)
+}
+
+func (r *PostgresRepository) ExtendJobLease(ctx context.Context, jobID string, attempt int, lease time.Duration) error {
+	query := `
+		UPDATE job SET
+			locked_until = NOW() + make_interval(secs => $3),
+			updated_at = NOW()
+		WHERE job_id = $1 AND status = 'RUNNING' AND attempts = $2
+	`
+	result, err := r.q.ExecContext(ctx, query, jobID, attempt, lease.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to extend job lease: %v", err)
+	}
+	return checkJobLease(result)
+}
+
+func (r *PostgresRepository) CompleteJob(ctx context.Context, jobID string, attempt int, result []byte) error {
+	query := `
+		UPDATE job SET
+			status = 'SUCCEEDED',
+			progress = total,
+			result = $3,
+			error = '',
+			locked_until = NULL,
+			finished_at = NOW(),
+			updated_at = NOW()
+		WHERE job_id = $1 AND status = 'RUNNING' AND attempts = $2
+	`
+	res, err := r.q.ExecContext(ctx, query, jobID, attempt, result)
+	if err != nil {
+		return fmt.Errorf("failed to complete job: %v", err)
+	}
+	return checkJobLease(res)
+}
+
+func (r *PostgresRepository) FailJob(ctx context.Context, jobID string, attempt int, message string) error {
+	query := `
+		UPDATE job SET
+			status = 'FAILED',
+			error = $3,
+			locked_until = NULL,
+			finished_at = NOW(),
+			updated_at = NOW()
+		WHERE job_id = $1 AND status = 'RUNNING' AND attempts = $2
+	`
+	result, err := r.q.ExecContext(ctx, query, jobID, attempt, message)
+	if err != nil {
+		return fmt.Errorf("failed to fail job: %v", err)
+	}
+	return checkJobLease(result)
+}
+
+// checkJobLease сообщает "job lease lost", если задача уже не выполняется этой попыткой:
+// аренду забрал другой исполнитель, и устаревшая попытка не должна менять его статус и результат
+func checkJobLease(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("job lease lost")
+	}
+	return nil
+}
+
+// FailExhaustedJobs помечает FAILED задачи, которые прерывались maxAttempts раз и больше не будут взяты в работу
+func (r *PostgresRepository) FailExhaustedJobs(ctx context.Context, maxAttempts int) (int, error) {
+	query := `
+		UPDATE job SET
+			status = 'FAILED',
+			error = 'job was interrupted too many times',
+			locked_until = NULL,
+			finished_at = NOW(),
+			updated_at = NOW()
+		WHERE attempts >= $1
+		AND (status = 'PENDING' OR (status = 'RUNNING' AND locked_until < NOW()))
+	`
+	result, err := r.q.ExecContext(ctx, query, maxAttempts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail exhausted jobs: %v", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rows), nil
+}
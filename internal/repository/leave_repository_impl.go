@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	models "pr_task/internal/model"
+
+	"github.com/lib/pq"
+)
+
+// exclusionViolation - код ошибки PostgreSQL при нарушении ограничения user_leave_no_overlap
+const exclusionViolation = "23P01"
+
+const leaveColumns = `leave_id, user_id, to_char(starts_on, 'YYYY-MM-DD'), to_char(ends_on, 'YYYY-MM-DD'), reason, auto_reassign, reassigned_at, created_at`
+
+func scanLeave(row interface{ Scan(dest ...any) error }) (*models.UserLeave, error) {
+	var leave models.UserLeave
+	err := row.Scan(
+		&leave.LeaveID,
+		&leave.UserID,
+		&leave.StartsOn,
+		&leave.EndsOn,
+		&leave.Reason,
+		&leave.AutoReassign,
+		&leave.ReassignedAt,
+		&leave.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &leave, nil
+}
+
+func (r *PostgresRepository) queryLeaves(ctx context.Context, query string, args ...any) ([]models.UserLeave, error) {
+	rows, err := r.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Println(err)
+		}
+	}(rows)
+
+	leaves := []models.UserLeave{}
+	for rows.Next() {
+		leave, err := scanLeave(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan error: %v", err)
+		}
+		leaves = append(leaves, *leave)
+	}
+	return leaves, rows.Err()
+}
+
+func (r *PostgresRepository) CreateUserLeave(ctx context.Context, leave *models.UserLeave) error {
+	query := `
+		INSERT INTO user_leave (user_id, starts_on, ends_on, reason, auto_reassign)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + leaveColumns
+	created, err := scanLeave(r.q.QueryRowContext(ctx, query, leave.UserID, leave.StartsOn, leave.EndsOn, leave.Reason, leave.AutoReassign))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == exclusionViolation {
+			return fmt.Errorf("leave overlaps")
+		}
+		return fmt.Errorf("failed to create user leave: %v", err)
+	}
+	*leave = *created
+	return nil
+}
+
+func (r *PostgresRepository) GetUserLeaves(ctx context.Context, userID string) ([]models.UserLeave, error) {
+	query := `SELECT ` + leaveColumns + ` FROM user_leave WHERE user_id = $1 ORDER BY starts_on, leave_id`
+	return r.queryLeaves(ctx, query, userID)
+}
+
+func (r *PostgresRepository) HasOverlappingLeave(ctx context.Context, userID, startsOn, endsOn string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM user_leave
+			WHERE user_id = $1 AND starts_on <= $3::date AND ends_on >= $2::date
+		)
+	`
+	var exists bool
+	if err := r.q.QueryRowContext(ctx, query, userID, startsOn, endsOn).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r *PostgresRepository) DeleteUserLeave(ctx context.Context, leaveID string) (*models.UserLeave, error) {
+	query := `DELETE FROM user_leave WHERE leave_id = $1 RETURNING ` + leaveColumns
+	leave, err := scanLeave(r.q.QueryRowContext(ctx, query, leaveID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("leave not found")
+		}
+		return nil, err
+	}
+	return leave, nil
+}
+
+func (r *PostgresRepository) GetStartedLeaves(ctx context.Context) ([]models.UserLeave, error) {
+	query := `
+		SELECT ` + leaveColumns + ` FROM user_leave
+		WHERE auto_reassign AND reassigned_at IS NULL
+		AND CURRENT_DATE BETWEEN starts_on AND ends_on
+		ORDER BY starts_on, leave_id
+	`
+	return r.queryLeaves(ctx, query)
+}
+
+// ClaimStartedLeaves отмечает периоды одним UPDATE: строки блокируются до конца транзакции,
+// поэтому параллельные исполнители не передают ревью одного периода дважды
+func (r *PostgresRepository) ClaimStartedLeaves(ctx context.Context, userID string) ([]models.UserLeave, error) {
+	query := `
+		UPDATE user_leave SET reassigned_at = NOW()
+		WHERE auto_reassign AND reassigned_at IS NULL
+		AND CURRENT_DATE BETWEEN starts_on AND ends_on
+		AND ($1 = '' OR user_id = $1)
+		RETURNING ` + leaveColumns
+	return r.queryLeaves(ctx, query, userID)
+}
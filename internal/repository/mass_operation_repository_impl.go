@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	models "pr_task/internal/model"
+)
+
+func (r *PostgresRepository) MassActivateUsers(ctx context.Context, teamName string, userIDs []string) ([]string, error) {
+	query := `
+		UPDATE "user" SET is_active = true
+		WHERE is_active = false
+		AND ($1 = '' OR team_name = $1)
+		AND ($2::text[] IS NULL OR user_id = ANY($2))
+		RETURNING user_id
+	`
+	var ids interface{}
+	if len(userIDs) > 0 {
+		ids = pq.Array(userIDs)
+	}
+
+	rows, err := r.q.QueryContext(ctx, query, teamName, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mass activate users: %v", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Printf("failed to close rows: %v", err)
+		}
+	}(rows)
+
+	var activated []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("scan error: %v", err)
+		}
+		activated = append(activated, userID)
+	}
+
+	return activated, rows.Err()
+}
+
+func (r *PostgresRepository) CreateMassDeactivationOperation(ctx context.Context, op *models.MassDeactivationOperation) error {
+	changes, err := json.Marshal(op.PRChanges)
+	if err != nil {
+		return fmt.Errorf("failed to marshal PR changes: %v", err)
+	}
+
+	query := `
+		INSERT INTO mass_deactivation (team_name, deactivated_user_ids, pr_changes)
+		VALUES ($1, $2, $3)
+		RETURNING operation_id, created_at
+	`
+	err = r.q.QueryRowContext(ctx, query, op.TeamName, pq.Array(op.DeactivatedUserIDs), changes).Scan(&op.OperationID, &op.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save mass deactivation: %v", err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetMassDeactivationOperation(ctx context.Context, operationID string) (*models.MassDeactivationOperation, error) {
+	var op models.MassDeactivationOperation
+	var changes []byte
+	query := `
+		SELECT operation_id, team_name, deactivated_user_ids, pr_changes, created_at, undone_at
+		FROM mass_deactivation
+		WHERE operation_id = $1
+	`
+	err := r.q.QueryRowContext(ctx, query, operationID).Scan(
+		&op.OperationID,
+		&op.TeamName,
+		pq.Array(&op.DeactivatedUserIDs),
+		&changes,
+		&op.CreatedAt,
+		&op.UndoneAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("operation not found")
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(changes, &op.PRChanges); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal PR changes: %v", err)
+	}
+	return &op, nil
+}
+
+func (r *PostgresRepository) MarkMassDeactivationUndone(ctx context.Context, operationID string) error {
+	query := `UPDATE mass_deactivation SET undone_at = NOW() WHERE operation_id = $1 AND undone_at IS NULL`
+	result, err := r.q.ExecContext(ctx, query, operationID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("operation already undone")
+	}
+	return nil
+}
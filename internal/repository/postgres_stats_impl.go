@@ -13,17 +13,19 @@ func (r *PostgresRepository) GetReviewStatsByUser(ctx context.Context) ([]models
             u.user_id,
             u.username, 
             u.team_name,
-            (
-                SELECT COUNT(*) 
-                FROM pull_request pr 
-                WHERE u.user_id = ANY(pr.assigned_reviewers)
-            ) as review_count
+            COUNT(pr.pull_request_id) as review_count,
+            COUNT(pr.pull_request_id) FILTER (WHERE pr.status = 'OPEN') as open_reviews,
+            COALESCE(u.max_open_reviews, t.default_max_open_reviews) as max_open_reviews
         FROM "user" u
+        JOIN team t ON t.team_name = u.team_name
+        LEFT JOIN pr_reviewer r ON r.user_id = u.user_id
+        LEFT JOIN pull_request pr ON pr.pull_request_id = r.pull_request_id AND pr.status <> 'CLOSED'
         WHERE u.is_active = true
+        GROUP BY u.user_id, u.username, u.team_name, t.default_max_open_reviews
         ORDER BY review_count DESC
     `
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.q.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user review stats: %v", err)
 	}
@@ -37,7 +39,7 @@ func (r *PostgresRepository) GetReviewStatsByUser(ctx context.Context) ([]models
 	var stats []models.UserReviewStats
 	for rows.Next() {
 		var stat models.UserReviewStats
-		if err := rows.Scan(&stat.UserID, &stat.Username, &stat.TeamName, &stat.ReviewCount); err != nil {
+		if err := rows.Scan(&stat.UserID, &stat.Username, &stat.TeamName, &stat.ReviewCount, &stat.OpenReviews, &stat.MaxOpenReviews); err != nil {
 			return nil, err
 		}
 		stats = append(stats, stat)
@@ -49,16 +51,18 @@ func (r *PostgresRepository) GetReviewStatsByUser(ctx context.Context) ([]models
 func (r *PostgresRepository) GetReviewStatsByPR(ctx context.Context) ([]models.PRReviewStats, error) {
 	query := `
 		SELECT 
-			pull_request_id,
-			pull_request_name,
-			author_id,
-			status,
-			COALESCE(array_length(assigned_reviewers, 1), 0) as reviewer_count
-		FROM pull_request
-		ORDER BY created_at DESC
+			pr.pull_request_id,
+			pr.pull_request_name,
+			COALESCE(pr.author_id, ''),
+			pr.status,
+			COUNT(r.user_id) as reviewer_count
+		FROM pull_request pr
+		LEFT JOIN pr_reviewer r ON r.pull_request_id = pr.pull_request_id
+		GROUP BY pr.pull_request_id
+		ORDER BY pr.created_at DESC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := r.q.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PR review stats: %v", err)
 	}
@@ -87,17 +91,27 @@ func (r *PostgresRepository) GetOverallStats(ctx context.Context) (*models.Overa
 			(SELECT COUNT(*) FROM pull_request) as total_prs,
 			(SELECT COUNT(*) FROM pull_request WHERE status = 'OPEN') as open_prs,
 			(SELECT COUNT(*) FROM pull_request WHERE status = 'MERGED') as merged_prs,
+			(SELECT COUNT(*) FROM pull_request WHERE status = 'CLOSED') as closed_prs,
+			(SELECT COUNT(*) FROM pull_request WHERE status = 'DRAFT') as draft_prs,
 			(SELECT COUNT(*) FROM "user") as total_users,
 			(SELECT COUNT(*) FROM "user" WHERE is_active = true) as active_users,
-			(SELECT SUM(COALESCE(array_length(assigned_reviewers, 1), 0)) FROM pull_request) as total_reviews,
-			(SELECT AVG(COALESCE(array_length(assigned_reviewers, 1), 0)) FROM pull_request) as avg_reviews
+			(SELECT COUNT(*) FROM pr_reviewer r JOIN pull_request pr ON pr.pull_request_id = r.pull_request_id WHERE pr.status <> 'CLOSED') as total_reviews,
+			(SELECT COALESCE(AVG(reviewer_count), 0) FROM (
+				SELECT COUNT(r.user_id) as reviewer_count
+				FROM pull_request pr
+				LEFT JOIN pr_reviewer r ON r.pull_request_id = pr.pull_request_id
+				WHERE pr.status <> 'CLOSED'
+				GROUP BY pr.pull_request_id
+			) per_pr) as avg_reviews
 	`
 
 	var stats models.OverallStats
-	err := r.db.QueryRowContext(ctx, query).Scan(
+	err := r.q.QueryRowContext(ctx, query).Scan(
 		&stats.TotalPRs,
 		&stats.OpenPRs,
 		&stats.MergedPRs,
+		&stats.ClosedPRs,
+		&stats.DraftPRs,
 		&stats.TotalUsers,
 		&stats.ActiveUsers,
 		&stats.TotalReviews,
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/lib/pq"
+	"pr_task/internal/dto"
+	models "pr_task/internal/model"
+)
+
+// setPRReviewers приводит состав ревьюверов PR к reviewers. Оставшиеся ревьюверы сохраняют свои вердикты и assigned_by,
+// новые получают assignedBy, порядок в списке хранится в position
+func (r *PostgresRepository) setPRReviewers(ctx context.Context, prID string, reviewers []string, assignedBy string) error {
+	if reviewers == nil {
+		reviewers = []string{}
+	}
+
+	deleteQuery := `
+		DELETE FROM pr_reviewer
+		WHERE pull_request_id = $1 AND NOT (user_id = ANY($2))
+	`
+	if _, err := r.q.ExecContext(ctx, deleteQuery, prID, pq.Array(reviewers)); err != nil {
+		return fmt.Errorf("failed to remove reviewers of PR %s: %v", prID, err)
+	}
+
+	// Журнал назначений получает только новых ревьюверов PR, до их вставки в pr_reviewer
+	logQuery := `
+		INSERT INTO reviewer_assignment (pull_request_id, user_id, assigned_by)
+		SELECT $1, reviewer.user_id, $3
+		FROM unnest($2::text[]) WITH ORDINALITY AS reviewer(user_id, position)
+		WHERE NOT EXISTS (SELECT 1 FROM pr_reviewer r WHERE r.pull_request_id = $1 AND r.user_id = reviewer.user_id)
+		ORDER BY reviewer.position
+	`
+	if _, err := r.q.ExecContext(ctx, logQuery, prID, pq.Array(reviewers), assignedBy); err != nil {
+		return fmt.Errorf("failed to log reviewer assignments of PR %s: %v", prID, err)
+	}
+
+	upsertQuery := `
+		INSERT INTO pr_reviewer (pull_request_id, user_id, position, assigned_by)
+		SELECT $1, reviewer.user_id, reviewer.position, $3
+		FROM unnest($2::text[]) WITH ORDINALITY AS reviewer(user_id, position)
+		ON CONFLICT (pull_request_id, user_id) DO UPDATE SET position = EXCLUDED.position
+	`
+	if _, err := r.q.ExecContext(ctx, upsertQuery, prID, pq.Array(reviewers), assignedBy); err != nil {
+		return fmt.Errorf("failed to assign reviewers of PR %s: %v", prID, err)
+	}
+	return nil
+}
+
+func (r *PostgresRepository) SetReviewerFallbackTeams(ctx context.Context, prID string, fallbackTeams map[string]string) error {
+	query := `UPDATE pr_reviewer SET fallback_team = $3 WHERE pull_request_id = $1 AND user_id = $2`
+	for userID, teamName := range fallbackTeams {
+		if _, err := r.q.ExecContext(ctx, query, prID, userID, teamName); err != nil {
+			return fmt.Errorf("failed to set fallback team of reviewer %s in PR %s: %v", userID, prID, err)
+		}
+	}
+	return nil
+}
+
+func (r *PostgresRepository) SetReviewerExplanations(ctx context.Context, prID string, explanations map[string]*models.AssignmentExplanation) error {
+	query := `UPDATE pr_reviewer SET explanation = $3 WHERE pull_request_id = $1 AND user_id = $2`
+	for userID, explanation := range explanations {
+		data, err := json.Marshal(explanation)
+		if err != nil {
+			return fmt.Errorf("failed to marshal explanation of reviewer %s: %v", userID, err)
+		}
+		if _, err := r.q.ExecContext(ctx, query, prID, userID, data); err != nil {
+			return fmt.Errorf("failed to set explanation of reviewer %s in PR %s: %v", userID, prID, err)
+		}
+	}
+	return nil
+}
+
+// loadPRReviewers заполняет AssignedReviewers, Reviews, FallbackReviewers и AssignmentExplanations у переданных PR одним запросом
+func (r *PostgresRepository) loadPRReviewers(ctx context.Context, prs []dto.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(prs))
+	prIDs := make([]string, len(prs))
+	for i := range prs {
+		index[prs[i].PullRequestID] = i
+		prIDs[i] = prs[i].PullRequestID
+		prs[i].AssignedReviewers = []string{}
+		prs[i].Reviews = []models.PRReviewer{}
+	}
+
+	query := `
+		SELECT pull_request_id, user_id, state, assigned_at, assigned_by, reviewed_at, COALESCE(fallback_team, ''), explanation
+		FROM pr_reviewer
+		WHERE pull_request_id = ANY($1)
+		ORDER BY pull_request_id, position
+	`
+	rows, err := r.q.QueryContext(ctx, query, pq.Array(prIDs))
+	if err != nil {
+		return fmt.Errorf("failed to load PR reviewers: %v", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Printf("failed to close rows: %v", err)
+		}
+	}(rows)
+
+	for rows.Next() {
+		var prID string
+		var reviewer models.PRReviewer
+		var explanation []byte
+		if err := rows.Scan(&prID, &reviewer.UserID, &reviewer.State, &reviewer.AssignedAt, &reviewer.AssignedBy, &reviewer.ReviewedAt, &reviewer.FallbackTeam, &explanation); err != nil {
+			return fmt.Errorf("scan error: %v", err)
+		}
+
+		pr := &prs[index[prID]]
+		pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer.UserID)
+		pr.Reviews = append(pr.Reviews, reviewer)
+		if reviewer.FallbackTeam != "" {
+			if pr.FallbackReviewers == nil {
+				pr.FallbackReviewers = map[string]string{}
+			}
+			pr.FallbackReviewers[reviewer.UserID] = reviewer.FallbackTeam
+		}
+		if explanation != nil {
+			if pr.AssignmentExplanations == nil {
+				pr.AssignmentExplanations = map[string]*models.AssignmentExplanation{}
+			}
+			var parsed models.AssignmentExplanation
+			if err := json.Unmarshal(explanation, &parsed); err != nil {
+				return fmt.Errorf("failed to unmarshal explanation of reviewer %s: %v", reviewer.UserID, err)
+			}
+			pr.AssignmentExplanations[reviewer.UserID] = &parsed
+		}
+	}
+	return rows.Err()
+}
+
+func (r *PostgresRepository) SetReviewState(ctx context.Context, prID, userID, state string) error {
+	query := `
+		UPDATE pr_reviewer SET state = $3, reviewed_at = NOW()
+		WHERE pull_request_id = $1 AND user_id = $2
+	`
+	result, err := r.q.ExecContext(ctx, query, prID, userID, state)
+	if err != nil {
+		return fmt.Errorf("failed to set review state: %v", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("reviewer not assigned")
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetAuthorReviewHistory(ctx context.Context, authorID, excludePRID string, lastPRs int) ([]models.ReviewAssignment, error) {
+	// Каждый ревьювер PR учитывается один раз, по первому назначению, даже если его снимали и назначали снова
+	query := `
+		SELECT pr.pull_request_id, pr.author_id, a.user_id, a.assigned_at, a.assigned_by
+		FROM (
+			SELECT pull_request_id, author_id, created_at
+			FROM pull_request
+			WHERE author_id = $1 AND pull_request_id <> $2 AND status <> 'DRAFT'
+			ORDER BY created_at DESC, pull_request_id DESC
+			LIMIT $3
+		) pr
+		JOIN LATERAL (
+			SELECT DISTINCT ON (user_id) assignment_id, user_id, assigned_at, assigned_by
+			FROM reviewer_assignment
+			WHERE pull_request_id = pr.pull_request_id
+			ORDER BY user_id, assignment_id
+		) a ON TRUE
+		ORDER BY pr.created_at DESC, pr.pull_request_id DESC, a.assignment_id
+	`
+	rows, err := r.q.QueryContext(ctx, query, authorID, excludePRID, lastPRs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review history: %v", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Println(err)
+		}
+	}(rows)
+
+	history := []models.ReviewAssignment{}
+	for rows.Next() {
+		var assignment models.ReviewAssignment
+		if err := rows.Scan(&assignment.PRID, &assignment.AuthorID, &assignment.ReviewerID, &assignment.AssignedAt, &assignment.AssignedBy); err != nil {
+			return nil, fmt.Errorf("scan error: %v", err)
+		}
+		history = append(history, assignment)
+	}
+	return history, rows.Err()
+}
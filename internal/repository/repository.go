@@ -9,25 +9,98 @@ import (
 )
 
 type Repository interface {
+	// WithTx выполняет fn в одной транзакции: ошибка из fn откатывает все изменения, сделанные через переданный репозиторий
+	WithTx(ctx context.Context, fn func(repo Repository) error) error
+	// WithReadOnlyTx выполняет fn в транзакции только для чтения: fn видит согласованный срез данных и не может ничего записать
+	WithReadOnlyTx(ctx context.Context, fn func(repo Repository) error) error
+
 	CreateTeam(ctx context.Context, team models.Team) error
 	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
 	TeamExists(ctx context.Context, teamName string) (bool, error)
+	ListTeams(ctx context.Context, limit, offset int) ([]models.Team, int, error)
+	// RenameTeam меняет имя команды, team_name пользователей и связанных записей обновляется каскадно
+	RenameTeam(ctx context.Context, teamName, newTeamName string) error
+	// DeleteTeam удаляет команду вместе с её участниками
+	DeleteTeam(ctx context.Context, teamName string) error
+	GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+	// UpdateTeamSettings сохраняет настройки команды вместе со списком запасных команд
+	UpdateTeamSettings(ctx context.Context, settings models.TeamSettings) error
+	AdvanceRotationCursor(ctx context.Context, teamName string, advance func(cursor string) string) error
+	// GetRotationCursor возвращает курсор ротации команды без блокировки, "" - ротация ещё не начиналась
+	GetRotationCursor(ctx context.Context, teamName string) (string, error)
 
 	CreateOrUpdateUser(ctx context.Context, user models.TeamMember, teamName string) error
 	GetUser(ctx context.Context, userID string) (*models.User, error)
+	ListUsers(ctx context.Context, filter models.UserFilter) ([]models.User, error)
+	UpdateUsername(ctx context.Context, userID, username string) error
 	UpdateUserActive(ctx context.Context, userID string, isActive bool) error
-	GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]models.User, error)
-	GetRandomActiveTeamMember(ctx context.Context, teamName string, excludeUserIDs []string) (*models.User, error)
-	MassDeactivateUsers(ctx context.Context, teamName string, excludeUserIDs []string) (int, error)
-	GetOpenPRsWithReviewers(ctx context.Context, teamName string) ([]models.OpenPRInfo, error)
-	UpdatePRReviewersBatch(ctx context.Context, updates []models.PRReviewersUpdate) error
+	// UpdateUserMaxOpenReviews задаёт личный предел открытых ревью, nil - предел команды по умолчанию
+	UpdateUserMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) error
+	// UpdateUserTags заменяет теги навыков пользователя
+	UpdateUserTags(ctx context.Context, userID string, tags []string) error
+	GetTagUsage(ctx context.Context) ([]dto.TagUsage, error)
+	UpdateUserTeam(ctx context.Context, userID, teamName string) error
+	DeleteUsers(ctx context.Context, userIDs []string) error
+	// CreateUserLeave сохраняет период отсутствия, заполняя LeaveID и CreatedAt
+	CreateUserLeave(ctx context.Context, leave *models.UserLeave) error
+	GetUserLeaves(ctx context.Context, userID string) ([]models.UserLeave, error)
+	// HasOverlappingLeave сообщает, пересекается ли период [startsOn, endsOn] с уже сохранёнными периодами пользователя
+	HasOverlappingLeave(ctx context.Context, userID, startsOn, endsOn string) (bool, error)
+	DeleteUserLeave(ctx context.Context, leaveID string) (*models.UserLeave, error)
+	// GetStartedLeaves возвращает начавшиеся периоды с auto_reassign, по которым открытые ревью ещё не передавались, не отмечая их
+	GetStartedLeaves(ctx context.Context) ([]models.UserLeave, error)
+	// ClaimStartedLeaves отмечает начавшиеся периоды с auto_reassign, по которым открытые ревью ещё не передавались, и возвращает их.
+	// Пустой userID означает периоды всех пользователей
+	ClaimStartedLeaves(ctx context.Context, userID string) ([]models.UserLeave, error)
+	// GetUserAvailability возвращает пользователей из userIDs и участников команд teamNames, включая неактивных,
+	// с отметкой отсутствия сегодня по календарю
+	GetUserAvailability(ctx context.Context, userIDs, teamNames []string) ([]models.UserAvailability, error)
+	GetOpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error)
+	MassDeactivateUsers(ctx context.Context, teamName string, excludeUserIDs []string) ([]string, error)
+	// GetMassDeactivationTargets возвращает пользователей, которых деактивировал бы MassDeactivateUsers, ничего не меняя
+	GetMassDeactivationTargets(ctx context.Context, teamName string, excludeUserIDs []string) ([]string, error)
+	GetOpenPRsByReviewers(ctx context.Context, reviewerIDs []string) ([]models.OpenPRInfo, error)
+	// GetOpenPRsByAuthors возвращает открытые PR и черновики авторов
+	GetOpenPRsByAuthors(ctx context.Context, authorIDs []string) ([]models.OpenPRInfo, error)
+	UpdatePRReviewersBatch(ctx context.Context, updates []models.PRReviewersUpdate, assignedBy string) error
+	MassActivateUsers(ctx context.Context, teamName string, userIDs []string) ([]string, error)
+	CreateMassDeactivationOperation(ctx context.Context, op *models.MassDeactivationOperation) error
+	GetMassDeactivationOperation(ctx context.Context, operationID string) (*models.MassDeactivationOperation, error)
+	MarkMassDeactivationUndone(ctx context.Context, operationID string) error
 
-	CreatePR(ctx context.Context, pr dto.PullRequest) error
+	// assignedBy - источник назначения ревьюверов (create, reassign, mass_deactivation, ...), сохраняется в pr_reviewer
+	CreatePR(ctx context.Context, pr dto.PullRequest, assignedBy string) error
 	GetPR(ctx context.Context, prID string) (*dto.PullRequest, error)
 	PRExists(ctx context.Context, prID string) (bool, error)
-	UpdatePRStatus(ctx context.Context, prID string, status string, mergedAt *time.Time) error
-	UpdatePRReviewers(ctx context.Context, prID string, reviewers []string) error
+	UpdatePRStatus(ctx context.Context, prID string, status string, mergedAt, closedAt *time.Time) error
+	RecordMerge(ctx context.Context, prID, mergedBy string, force bool, bypassed []string) error
+	UpdatePRReviewers(ctx context.Context, prID string, reviewers []string, assignedBy string) error
+	// SetReviewerFallbackTeams отмечает ревьюверов PR, назначенных из запасных команд (user_id -> команда)
+	SetReviewerFallbackTeams(ctx context.Context, prID string, fallbackTeams map[string]string) error
+	// SetReviewerExplanations сохраняет объяснения выбора ревьюверов PR (user_id -> объяснение)
+	SetReviewerExplanations(ctx context.Context, prID string, explanations map[string]*models.AssignmentExplanation) error
+	UpdatePRAuthor(ctx context.Context, prID, authorID string) error
 	GetPRsByReviewer(ctx context.Context, userID string) ([]dto.PullRequest, error)
+	SetReviewState(ctx context.Context, prID, userID, state string) error
+
+	// GetAuthorReviewHistory возвращает назначения ревьюверов на последние lastPRs PR автора (кроме черновиков и excludePRID),
+	// от новых PR к старым. Назначения читаются из журнала, поэтому включают ревьюверов, снятых с PR позже
+	// (переназначение, массовая деактивация, отсутствие, перевод и удаление пользователей)
+	GetAuthorReviewHistory(ctx context.Context, authorID, excludePRID string, lastPRs int) ([]models.ReviewAssignment, error)
+
+	GetCodeOwnerRules(ctx context.Context) ([]models.CodeOwnerRule, error)
+	// ReplaceCodeOwnerRules заменяет реестр владельцев кода, сохраняя порядок правил
+	ReplaceCodeOwnerRules(ctx context.Context, rules []models.CodeOwnerRule) error
+
+	CreateJob(ctx context.Context, job *models.Job) error
+	GetJob(ctx context.Context, jobID string) (*models.Job, error)
+	ClaimJob(ctx context.Context, lease time.Duration, maxAttempts int) (*models.Job, error)
+	// UpdateJobProgress, ExtendJobLease, CompleteJob и FailJob действуют, только пока задача выполняется попыткой attempt из ClaimJob
+	UpdateJobProgress(ctx context.Context, jobID string, attempt, progress, total int, lease time.Duration) error
+	ExtendJobLease(ctx context.Context, jobID string, attempt int, lease time.Duration) error
+	CompleteJob(ctx context.Context, jobID string, attempt int, result []byte) error
+	FailJob(ctx context.Context, jobID string, attempt int, message string) error
+	FailExhaustedJobs(ctx context.Context, maxAttempts int) (int, error)
 
 	GetReviewStatsByUser(ctx context.Context) ([]models.UserReviewStats, error)
 	GetReviewStatsByPR(ctx context.Context) ([]models.PRReviewStats, error)
@@ -7,30 +7,33 @@ import (
 	"fmt"
 	"github.com/lib/pq"
 	"pr_task/internal/dto"
-	"strings"
 	"time"
 
 	"pr_task/internal/model"
 )
 
+// uniqueViolation - код ошибки PostgreSQL при нарушении уникальности
+const uniqueViolation = "23505"
+
 type PostgresRepository struct {
 	db *sql.DB
+	q  querier
 }
 
 func NewPostgresRepository(db *sql.DB) Repository {
-	return &PostgresRepository{db: db}
+	return &PostgresRepository{db: db, q: db}
 }
 
 func (r *PostgresRepository) CreateTeam(ctx context.Context, team models.Team) error {
 	query := `INSERT INTO team (team_name) VALUES ($1)`
-	_, err := r.db.ExecContext(ctx, query, team.TeamName)
+	_, err := r.q.ExecContext(ctx, query, team.TeamName)
 	return err
 }
 
 func (r *PostgresRepository) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
 	var team models.Team
 	query := `SELECT team_name FROM team WHERE team_name = $1`
-	err := r.db.QueryRowContext(ctx, query, teamName).Scan(&team.TeamName)
+	err := r.q.QueryRowContext(ctx, query, teamName).Scan(&team.TeamName)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("team not found")
@@ -38,8 +41,8 @@ func (r *PostgresRepository) GetTeam(ctx context.Context, teamName string) (*mod
 		return nil, err
 	}
 
-	membersQuery := `SELECT user_id, username, is_active FROM "user" WHERE team_name = $1`
-	rows, err := r.db.QueryContext(ctx, membersQuery, teamName)
+	membersQuery := `SELECT user_id, username, is_active, is_lead, tags FROM "user" WHERE team_name = $1`
+	rows, err := r.q.QueryContext(ctx, membersQuery, teamName)
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +56,7 @@ func (r *PostgresRepository) GetTeam(ctx context.Context, teamName string) (*mod
 	var members []models.TeamMember
 	for rows.Next() {
 		var member models.TeamMember
-		if err := rows.Scan(&member.UserID, &member.Username, &member.IsActive); err != nil {
+		if err := rows.Scan(&member.UserID, &member.Username, &member.IsActive, &member.IsLead, pq.Array(&member.Tags)); err != nil {
 			return nil, err
 		}
 		members = append(members, member)
@@ -66,25 +69,26 @@ func (r *PostgresRepository) GetTeam(ctx context.Context, teamName string) (*mod
 func (r *PostgresRepository) TeamExists(ctx context.Context, teamName string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM team WHERE team_name = $1)`
-	err := r.db.QueryRowContext(ctx, query, teamName).Scan(&exists)
+	err := r.q.QueryRowContext(ctx, query, teamName).Scan(&exists)
 	return exists, err
 }
 
 func (r *PostgresRepository) CreateOrUpdateUser(ctx context.Context, user models.TeamMember, teamName string) error {
 	query := `
-		INSERT INTO "user" (user_id, username, team_name, is_active) 
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO "user" (user_id, username, team_name, is_active, is_lead, tags) 
+		VALUES ($1, $2, $3, $4, $5, COALESCE($6::text[], '{}'))
 		ON CONFLICT (user_id) 
-		DO UPDATE SET username = $2, team_name = $3, is_active = $4
+		DO UPDATE SET username = $2, team_name = $3, is_active = $4, is_lead = $5, tags = COALESCE($6::text[], "user".tags)
 	`
-	_, err := r.db.ExecContext(ctx, query, user.UserID, user.Username, teamName, user.IsActive)
+	// Теги nil передаются как NULL и не меняют теги существующего пользователя
+	_, err := r.q.ExecContext(ctx, query, user.UserID, user.Username, teamName, user.IsActive, user.IsLead, pq.Array(user.Tags))
 	return err
 }
 
 func (r *PostgresRepository) GetUser(ctx context.Context, userID string) (*models.User, error) {
 	var user models.User
-	query := `SELECT user_id, username, team_name, is_active FROM "user" WHERE user_id = $1`
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive)
+	query := `SELECT user_id, username, team_name, is_active, is_lead, max_open_reviews, tags FROM "user" WHERE user_id = $1`
+	err := r.q.QueryRowContext(ctx, query, userID).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.IsLead, &user.MaxOpenReviews, pq.Array(&user.Tags))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("user not found")
@@ -94,9 +98,62 @@ func (r *PostgresRepository) GetUser(ctx context.Context, userID string) (*model
 	return &user, nil
 }
 
+// ListUsers возвращает до filter.Limit пользователей по возрастанию user_id после filter.Cursor.
+// Username ищется как подстрока без учёта регистра
+func (r *PostgresRepository) ListUsers(ctx context.Context, filter models.UserFilter) ([]models.User, error) {
+	query := `
+		SELECT user_id, username, team_name, is_active, is_lead, max_open_reviews, tags
+		FROM "user"
+		WHERE ($1 = '' OR team_name = $1)
+		AND ($2::boolean IS NULL OR is_active = $2)
+		AND ($3 = '' OR strpos(lower(username), lower($3)) > 0)
+		AND ($4 = '' OR $4 = ANY(tags))
+		AND user_id > $5
+		ORDER BY user_id
+		LIMIT $6
+	`
+	rows, err := r.q.QueryContext(ctx, query, filter.TeamName, filter.IsActive, filter.Username, filter.Tag, filter.Cursor, filter.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %v", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Println(err)
+		}
+	}(rows)
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.IsLead, &user.MaxOpenReviews, pq.Array(&user.Tags)); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *PostgresRepository) UpdateUsername(ctx context.Context, userID, username string) error {
+	query := `UPDATE "user" SET username = $1 WHERE user_id = $2`
+	result, err := r.q.ExecContext(ctx, query, username, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
 func (r *PostgresRepository) UpdateUserActive(ctx context.Context, userID string, isActive bool) error {
 	query := `UPDATE "user" SET is_active = $1 WHERE user_id = $2`
-	result, err := r.db.ExecContext(ctx, query, isActive, userID)
+	result, err := r.q.ExecContext(ctx, query, isActive, userID)
 	if err != nil {
 		return err
 	}
@@ -111,11 +168,55 @@ func (r *PostgresRepository) UpdateUserActive(ctx context.Context, userID string
 	return nil
 }
 
-func (r *PostgresRepository) GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]models.User, error) {
-	query := `SELECT user_id, username, team_name, is_active FROM "user" WHERE team_name = $1 AND is_active = true AND user_id != $2`
-	rows, err := r.db.QueryContext(ctx, query, teamName, excludeUserID)
+func (r *PostgresRepository) UpdateUserMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) error {
+	query := `UPDATE "user" SET max_open_reviews = $1 WHERE user_id = $2`
+	result, err := r.q.ExecContext(ctx, query, maxOpenReviews, userID)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+func (r *PostgresRepository) UpdateUserTags(ctx context.Context, userID string, tags []string) error {
+	if tags == nil {
+		tags = []string{}
+	}
+
+	query := `UPDATE "user" SET tags = $1 WHERE user_id = $2`
+	result, err := r.q.ExecContext(ctx, query, pq.Array(tags), userID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// GetTagUsage возвращает все теги навыков и число пользователей с каждым тегом по алфавиту
+func (r *PostgresRepository) GetTagUsage(ctx context.Context) ([]dto.TagUsage, error) {
+	query := `
+		SELECT tag, COUNT(*)
+		FROM "user", unnest(tags) AS tag
+		GROUP BY tag
+		ORDER BY tag
+	`
+	rows, err := r.q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %v", err)
 	}
 	defer func(rows *sql.Rows) {
 		err := rows.Close()
@@ -124,84 +225,198 @@ func (r *PostgresRepository) GetActiveTeamMembers(ctx context.Context, teamName
 		}
 	}(rows)
 
-	var users []models.User
+	tags := []dto.TagUsage{}
 	for rows.Next() {
-		var user models.User
-		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
+		var usage dto.TagUsage
+		if err := rows.Scan(&usage.Tag, &usage.Users); err != nil {
 			return nil, err
 		}
-		users = append(users, user)
+		tags = append(tags, usage)
 	}
-	return users, nil
+	return tags, rows.Err()
 }
 
-func (r *PostgresRepository) GetRandomActiveTeamMember(ctx context.Context, teamName string, excludeUserIDs []string) (*models.User, error) {
-	var placeholders []string
-	var args []interface{}
-	args = append(args, teamName)
+func (r *PostgresRepository) UpdateUserTeam(ctx context.Context, userID, teamName string) error {
+	query := `UPDATE "user" SET team_name = $1 WHERE user_id = $2`
+	result, err := r.q.ExecContext(ctx, query, teamName, userID)
+	if err != nil {
+		return err
+	}
 
-	for i, userID := range excludeUserIDs {
-		placeholders = append(placeholders, fmt.Sprintf("$%d", i+2))
-		args = append(args, userID)
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("user not found")
 	}
+	return nil
+}
 
-	excludeClause := ""
-	if len(excludeUserIDs) > 0 {
-		excludeClause = fmt.Sprintf("AND user_id NOT IN (%s)", strings.Join(placeholders, ", "))
+// DeleteUsers удаляет пользователей. Их назначения ревьюверами удаляются каскадно, а созданные ими PR остаются без автора
+func (r *PostgresRepository) DeleteUsers(ctx context.Context, userIDs []string) error {
+	query := `DELETE FROM "user" WHERE user_id = ANY($1)`
+	if _, err := r.q.ExecContext(ctx, query, pq.Array(userIDs)); err != nil {
+		return fmt.Errorf("failed to delete users: %v", err)
 	}
+	return nil
+}
 
+func (r *PostgresRepository) GetUserAvailability(ctx context.Context, userIDs, teamNames []string) ([]models.UserAvailability, error) {
 	query := `
-    SELECT user_id, username, team_name, is_active 
-    FROM "user" 
-    WHERE team_name = $1 AND is_active = true ` + excludeClause + `
-    ORDER BY RANDOM()
-    LIMIT 1
-`
-
-	var user models.User
-	err := r.db.QueryRowContext(ctx, query, args...).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive)
+		SELECT user_id, username, team_name, is_active, is_lead, max_open_reviews, tags,
+			EXISTS (
+				SELECT 1 FROM user_leave l
+				WHERE l.user_id = "user".user_id AND CURRENT_DATE BETWEEN l.starts_on AND l.ends_on
+			)
+		FROM "user"
+		WHERE user_id = ANY($1) OR team_name = ANY($2)
+		ORDER BY user_id
+	`
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+	if teamNames == nil {
+		teamNames = []string{}
+	}
+	rows, err := r.q.QueryContext(ctx, query, pq.Array(userIDs), pq.Array(teamNames))
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("no active team members available")
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Println(err)
+		}
+	}(rows)
+
+	var users []models.UserAvailability
+	for rows.Next() {
+		var user models.UserAvailability
+		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.IsLead, &user.MaxOpenReviews, pq.Array(&user.Tags), &user.OnLeave); err != nil {
+			return nil, err
 		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+func (r *PostgresRepository) queryUsers(ctx context.Context, query string, args ...any) ([]models.User, error) {
+	rows, err := r.q.QueryContext(ctx, query, args...)
+	if err != nil {
 		return nil, err
 	}
-	return &user, nil
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Println(err)
+		}
+	}(rows)
+
+	var users []models.User
+	for rows.Next() {
+		var user models.User
+		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &user.IsLead, &user.MaxOpenReviews, pq.Array(&user.Tags)); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
 }
 
-func (r *PostgresRepository) CreatePR(ctx context.Context, pr dto.PullRequest) error {
+func (r *PostgresRepository) GetOpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
 	query := `
-		INSERT INTO pull_request (pull_request_id, pull_request_name, author_id, status, assigned_reviewers, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		SELECT r.user_id, COUNT(*)
+		FROM pr_reviewer r
+		JOIN pull_request pr ON pr.pull_request_id = r.pull_request_id
+		WHERE pr.status = 'OPEN' AND r.user_id = ANY($1)
+		GROUP BY r.user_id
 	`
-	_, err := r.db.ExecContext(ctx, query,
-		pr.PullRequestID,
-		pr.PullRequestName,
-		pr.AuthorID,
-		pr.Status,
-		pq.Array(pr.AssignedReviewers),
-		pr.CreatedAt,
-	)
-	return err
+	rows, err := r.q.QueryContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Println(err)
+		}
+	}(rows)
+
+	counts := make(map[string]int, len(userIDs))
+	for rows.Next() {
+		var userID string
+		var count int
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		counts[userID] = count
+	}
+	return counts, nil
+}
+
+func (r *PostgresRepository) CreatePR(ctx context.Context, pr dto.PullRequest, assignedBy string) error {
+	return r.withTx(ctx, func(tx *PostgresRepository) error {
+		query := `
+			INSERT INTO pull_request (pull_request_id, pull_request_name, author_id, status, created_at, changed_files, required_tags)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+		`
+		changedFiles, requiredTags := pr.ChangedFiles, pr.RequiredTags
+		if changedFiles == nil {
+			changedFiles = []string{}
+		}
+		if requiredTags == nil {
+			requiredTags = []string{}
+		}
+		_, err := tx.q.ExecContext(ctx, query,
+			pr.PullRequestID,
+			pr.PullRequestName,
+			pr.AuthorID,
+			pr.Status,
+			pr.CreatedAt,
+			pq.Array(changedFiles),
+			pq.Array(requiredTags),
+		)
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+				return errors.New("PR already exists")
+			}
+			return err
+		}
+
+		if err := tx.setPRReviewers(ctx, pr.PullRequestID, pr.AssignedReviewers, assignedBy); err != nil {
+			return err
+		}
+		if err := tx.SetReviewerFallbackTeams(ctx, pr.PullRequestID, pr.FallbackReviewers); err != nil {
+			return err
+		}
+		return tx.SetReviewerExplanations(ctx, pr.PullRequestID, pr.AssignmentExplanations)
+	})
 }
 
 func (r *PostgresRepository) GetPR(ctx context.Context, prID string) (*dto.PullRequest, error) {
 	var pr dto.PullRequest
 	query := `
-		SELECT pull_request_id, pull_request_name, author_id, status, assigned_reviewers, created_at, merged_at
+		SELECT pull_request_id, pull_request_name, COALESCE(author_id, ''), status, created_at, merged_at, closed_at,
+			merged_by, force_merged, bypassed_conditions, changed_files, required_tags
 		FROM pull_request 
 		WHERE pull_request_id = $1
 	`
 
-	var reviewers []string
-	err := r.db.QueryRowContext(ctx, query, prID).Scan(
+	err := r.q.QueryRowContext(ctx, query, prID).Scan(
 		&pr.PullRequestID,
 		&pr.PullRequestName,
 		&pr.AuthorID,
 		&pr.Status,
-		pq.Array(&reviewers),
 		&pr.CreatedAt,
 		&pr.MergedAt,
+		&pr.ClosedAt,
+		&pr.MergedBy,
+		&pr.ForceMerged,
+		pq.Array(&pr.BypassedConditions),
+		pq.Array(&pr.ChangedFiles),
+		pq.Array(&pr.RequiredTags),
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -210,20 +425,45 @@ func (r *PostgresRepository) GetPR(ctx context.Context, prID string) (*dto.PullR
 		return nil, err
 	}
 
-	pr.AssignedReviewers = reviewers
-	return &pr, nil
+	prs := []dto.PullRequest{pr}
+	if err := r.loadPRReviewers(ctx, prs); err != nil {
+		return nil, err
+	}
+	return &prs[0], nil
 }
 
 func (r *PostgresRepository) PRExists(ctx context.Context, prID string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM pull_request WHERE pull_request_id = $1)`
-	err := r.db.QueryRowContext(ctx, query, prID).Scan(&exists)
+	err := r.q.QueryRowContext(ctx, query, prID).Scan(&exists)
 	return exists, err
 }
 
-func (r *PostgresRepository) UpdatePRStatus(ctx context.Context, prID string, status string, mergedAt *time.Time) error {
-	query := `UPDATE pull_request SET status = $1, merged_at = $2 WHERE pull_request_id = $3`
-	result, err := r.db.ExecContext(ctx, query, status, mergedAt, prID)
+func (r *PostgresRepository) UpdatePRStatus(ctx context.Context, prID string, status string, mergedAt, closedAt *time.Time) error {
+	query := `UPDATE pull_request SET status = $1, merged_at = $2, closed_at = $3 WHERE pull_request_id = $4`
+	result, err := r.q.ExecContext(ctx, query, status, mergedAt, closedAt, prID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("PR not found")
+	}
+	return nil
+}
+
+// RecordMerge сохраняет, кто смержил PR и какие условия политики мержа были обойдены флагом force
+func (r *PostgresRepository) RecordMerge(ctx context.Context, prID, mergedBy string, force bool, bypassed []string) error {
+	if bypassed == nil {
+		bypassed = []string{}
+	}
+
+	query := `UPDATE pull_request SET merged_by = $1, force_merged = $2, bypassed_conditions = $3 WHERE pull_request_id = $4`
+	result, err := r.q.ExecContext(ctx, query, mergedBy, force, pq.Array(bypassed), prID)
 	if err != nil {
 		return err
 	}
@@ -238,9 +478,24 @@ func (r *PostgresRepository) UpdatePRStatus(ctx context.Context, prID string, st
 	return nil
 }
 
-func (r *PostgresRepository) UpdatePRReviewers(ctx context.Context, prID string, reviewers []string) error {
-	query := `UPDATE pull_request SET assigned_reviewers = $1 WHERE pull_request_id = $2`
-	result, err := r.db.ExecContext(ctx, query, pq.Array(reviewers), prID)
+func (r *PostgresRepository) UpdatePRReviewers(ctx context.Context, prID string, reviewers []string, assignedBy string) error {
+	return r.withTx(ctx, func(tx *PostgresRepository) error {
+		var exists bool
+		query := `SELECT EXISTS(SELECT 1 FROM pull_request WHERE pull_request_id = $1 FOR UPDATE)`
+		if err := tx.q.QueryRowContext(ctx, query, prID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("PR not found")
+		}
+
+		return tx.setPRReviewers(ctx, prID, reviewers, assignedBy)
+	})
+}
+
+func (r *PostgresRepository) UpdatePRAuthor(ctx context.Context, prID, authorID string) error {
+	query := `UPDATE pull_request SET author_id = $1 WHERE pull_request_id = $2`
+	result, err := r.q.ExecContext(ctx, query, authorID, prID)
 	if err != nil {
 		return err
 	}
@@ -257,12 +512,13 @@ func (r *PostgresRepository) UpdatePRReviewers(ctx context.Context, prID string,
 
 func (r *PostgresRepository) GetPRsByReviewer(ctx context.Context, userID string) ([]dto.PullRequest, error) {
 	query := `
-		SELECT pull_request_id, pull_request_name, author_id, status, assigned_reviewers, created_at, merged_at
-		FROM pull_request 
-		WHERE $1 = ANY(assigned_reviewers)
-		ORDER BY created_at DESC
+		SELECT pr.pull_request_id, pr.pull_request_name, COALESCE(pr.author_id, ''), pr.status, pr.created_at, pr.merged_at, pr.closed_at
+		FROM pull_request pr
+		JOIN pr_reviewer r ON r.pull_request_id = pr.pull_request_id
+		WHERE r.user_id = $1 AND pr.status <> 'CLOSED'
+		ORDER BY pr.created_at DESC
 	`
-	rows, err := r.db.QueryContext(ctx, query, userID)
+	rows, err := r.q.QueryContext(ctx, query, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -276,21 +532,26 @@ func (r *PostgresRepository) GetPRsByReviewer(ctx context.Context, userID string
 	var prs []dto.PullRequest
 	for rows.Next() {
 		var pr dto.PullRequest
-		var reviewers []string
 		err := rows.Scan(
 			&pr.PullRequestID,
 			&pr.PullRequestName,
 			&pr.AuthorID,
 			&pr.Status,
-			pq.Array(&reviewers),
 			&pr.CreatedAt,
 			&pr.MergedAt,
+			&pr.ClosedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
-		pr.AssignedReviewers = reviewers
 		prs = append(prs, pr)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadPRReviewers(ctx, prs); err != nil {
+		return nil, err
+	}
 	return prs, nil
 }
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func (r *PostgresRepository) AdvanceRotationCursor(ctx context.Context, teamName string, advance func(cursor string) string) error {
+	return r.withTx(ctx, func(tx *PostgresRepository) error {
+		if _, err := tx.q.ExecContext(ctx, `INSERT INTO team_rotation (team_name) VALUES ($1) ON CONFLICT (team_name) DO NOTHING`, teamName); err != nil {
+			return fmt.Errorf("failed to init rotation cursor: %v", err)
+		}
+
+		var cursor string
+		query := `SELECT last_user_id FROM team_rotation WHERE team_name = $1 FOR UPDATE`
+		if err := tx.q.QueryRowContext(ctx, query, teamName).Scan(&cursor); err != nil {
+			return fmt.Errorf("failed to lock rotation cursor: %v", err)
+		}
+
+		next := advance(cursor)
+
+		query = `UPDATE team_rotation SET last_user_id = $1, updated_at = NOW() WHERE team_name = $2`
+		if _, err := tx.q.ExecContext(ctx, query, next, teamName); err != nil {
+			return fmt.Errorf("failed to advance rotation cursor: %v", err)
+		}
+		return nil
+	})
+}
+
+func (r *PostgresRepository) GetRotationCursor(ctx context.Context, teamName string) (string, error) {
+	var cursor string
+	query := `SELECT last_user_id FROM team_rotation WHERE team_name = $1`
+	err := r.q.QueryRowContext(ctx, query, teamName).Scan(&cursor)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("failed to get rotation cursor: %v", err)
+	}
+	return cursor, nil
+}
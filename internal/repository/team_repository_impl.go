@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	models "pr_task/internal/model"
+)
+
+// ListTeams возвращает страницу команд по алфавиту вместе с участниками и общее число команд
+func (r *PostgresRepository) ListTeams(ctx context.Context, limit, offset int) ([]models.Team, int, error) {
+	var total int
+	if err := r.q.QueryRowContext(ctx, `SELECT COUNT(*) FROM team`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count teams: %v", err)
+	}
+
+	query := `SELECT team_name FROM team ORDER BY team_name LIMIT $1 OFFSET $2`
+	rows, err := r.q.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list teams: %v", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Printf("failed to close rows: %v", err)
+		}
+	}(rows)
+
+	teams := []models.Team{}
+	index := make(map[string]int)
+	var teamNames []string
+	for rows.Next() {
+		team := models.Team{Members: []models.TeamMember{}}
+		if err := rows.Scan(&team.TeamName); err != nil {
+			return nil, 0, fmt.Errorf("scan error: %v", err)
+		}
+		index[team.TeamName] = len(teams)
+		teamNames = append(teamNames, team.TeamName)
+		teams = append(teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	if len(teams) == 0 {
+		return teams, total, nil
+	}
+
+	membersQuery := `
+		SELECT team_name, user_id, username, is_active, is_lead, tags
+		FROM "user"
+		WHERE team_name = ANY($1)
+		ORDER BY user_id
+	`
+	memberRows, err := r.q.QueryContext(ctx, membersQuery, pq.Array(teamNames))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load team members: %v", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Printf("failed to close rows: %v", err)
+		}
+	}(memberRows)
+
+	for memberRows.Next() {
+		var teamName string
+		var member models.TeamMember
+		if err := memberRows.Scan(&teamName, &member.UserID, &member.Username, &member.IsActive, &member.IsLead, pq.Array(&member.Tags)); err != nil {
+			return nil, 0, fmt.Errorf("scan error: %v", err)
+		}
+		team := &teams[index[teamName]]
+		team.Members = append(team.Members, member)
+	}
+	return teams, total, memberRows.Err()
+}
+
+func (r *PostgresRepository) RenameTeam(ctx context.Context, teamName, newTeamName string) error {
+	query := `UPDATE team SET team_name = $1 WHERE team_name = $2`
+	result, err := r.q.ExecContext(ctx, query, newTeamName, teamName)
+	if err != nil {
+		return fmt.Errorf("failed to rename team: %v", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("team not found")
+	}
+	return nil
+}
+
+func (r *PostgresRepository) DeleteTeam(ctx context.Context, teamName string) error {
+	result, err := r.q.ExecContext(ctx, `DELETE FROM team WHERE team_name = $1`, teamName)
+	if err != nil {
+		return fmt.Errorf("failed to delete team: %v", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("team not found")
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	models "pr_task/internal/model"
+)
+
+func (r *PostgresRepository) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	var settings models.TeamSettings
+	query := `
+		SELECT team_name, reviewer_strategy, min_reviewers, max_reviewers,
+			required_approvals, block_on_changes_requested, require_lead_approval, default_max_open_reviews, pairing_lookback
+		FROM team WHERE team_name = $1
+	`
+	err := r.q.QueryRowContext(ctx, query, teamName).Scan(
+		&settings.TeamName,
+		&settings.ReviewerStrategy,
+		&settings.MinReviewers,
+		&settings.MaxReviewers,
+		&settings.RequiredApprovals,
+		&settings.BlockOnChangesRequested,
+		&settings.RequireLeadApproval,
+		&settings.DefaultMaxOpenReviews,
+		&settings.PairingLookback,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("team not found")
+		}
+		return nil, err
+	}
+
+	settings.FallbackTeams, err = r.getFallbackTeams(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+func (r *PostgresRepository) getFallbackTeams(ctx context.Context, teamName string) ([]string, error) {
+	query := `SELECT fallback_team_name FROM team_fallback WHERE team_name = $1 ORDER BY position`
+	rows, err := r.q.QueryContext(ctx, query, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fallback teams: %v", err)
+	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Println(err)
+		}
+	}(rows)
+
+	fallbackTeams := []string{}
+	for rows.Next() {
+		var fallbackTeam string
+		if err := rows.Scan(&fallbackTeam); err != nil {
+			return nil, err
+		}
+		fallbackTeams = append(fallbackTeams, fallbackTeam)
+	}
+	return fallbackTeams, rows.Err()
+}
+
+func (r *PostgresRepository) UpdateTeamSettings(ctx context.Context, settings models.TeamSettings) error {
+	return r.withTx(ctx, func(tx *PostgresRepository) error {
+		if err := tx.updateTeamSettings(ctx, settings); err != nil {
+			return err
+		}
+		return tx.setFallbackTeams(ctx, settings.TeamName, settings.FallbackTeams)
+	})
+}
+
+func (r *PostgresRepository) updateTeamSettings(ctx context.Context, settings models.TeamSettings) error {
+	query := `
+		UPDATE team SET
+			reviewer_strategy = $1,
+			min_reviewers = $2,
+			max_reviewers = $3,
+			required_approvals = $4,
+			block_on_changes_requested = $5,
+			require_lead_approval = $6,
+			default_max_open_reviews = $7,
+			pairing_lookback = $8
+		WHERE team_name = $9
+	`
+	result, err := r.q.ExecContext(ctx, query,
+		settings.ReviewerStrategy,
+		settings.MinReviewers,
+		settings.MaxReviewers,
+		settings.RequiredApprovals,
+		settings.BlockOnChangesRequested,
+		settings.RequireLeadApproval,
+		settings.DefaultMaxOpenReviews,
+		settings.PairingLookback,
+		settings.TeamName,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("team not found")
+	}
+	return nil
+}
+
+// setFallbackTeams заменяет запасные команды, порядок в списке хранится в position
+func (r *PostgresRepository) setFallbackTeams(ctx context.Context, teamName string, fallbackTeams []string) error {
+	if fallbackTeams == nil {
+		fallbackTeams = []string{}
+	}
+
+	if _, err := r.q.ExecContext(ctx, `DELETE FROM team_fallback WHERE team_name = $1`, teamName); err != nil {
+		return fmt.Errorf("failed to clear fallback teams: %v", err)
+	}
+
+	query := `
+		INSERT INTO team_fallback (team_name, fallback_team_name, position)
+		SELECT $1, fallback.team_name, fallback.position
+		FROM unnest($2::text[]) WITH ORDINALITY AS fallback(team_name, position)
+	`
+	if _, err := r.q.ExecContext(ctx, query, teamName, pq.Array(fallbackTeams)); err != nil {
+		return fmt.Errorf("failed to set fallback teams: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// querier - общий интерфейс *sql.DB и *sql.Tx, через который репозиторий выполняет запросы
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+func (r *PostgresRepository) WithTx(ctx context.Context, fn func(repo Repository) error) error {
+	return r.withTx(ctx, func(tx *PostgresRepository) error {
+		return fn(tx)
+	})
+}
+
+func (r *PostgresRepository) WithReadOnlyTx(ctx context.Context, fn func(repo Repository) error) error {
+	return r.withTxOptions(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *PostgresRepository) error {
+		return fn(tx)
+	})
+}
+
+// withTx выполняет fn в транзакции. Если репозиторий уже работает внутри транзакции, fn выполняется в ней же
+func (r *PostgresRepository) withTx(ctx context.Context, fn func(tx *PostgresRepository) error) error {
+	return r.withTxOptions(ctx, nil, fn)
+}
+
+func (r *PostgresRepository) withTxOptions(ctx context.Context, opts *sql.TxOptions, fn func(tx *PostgresRepository) error) error {
+	if _, ok := r.q.(*sql.Tx); ok {
+		return fn(r)
+	}
+
+	tx, err := r.db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer func(tx *sql.Tx) {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			fmt.Printf("failed to rollback transaction: %v", err)
+		}
+	}(tx)
+
+	if err := fn(&PostgresRepository{db: r.db, q: tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %v", err)
+	}
+	return nil
+}
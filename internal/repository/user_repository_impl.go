@@ -5,47 +5,95 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/lib/pq"
+	"pr_task/internal/dto"
 	models "pr_task/internal/model"
 )
 
-func (r *PostgresRepository) MassDeactivateUsers(ctx context.Context, teamName string, excludeUserIDs []string) (int, error) {
-	var query string
-	var args []interface{}
+func (r *PostgresRepository) MassDeactivateUsers(ctx context.Context, teamName string, excludeUserIDs []string) ([]string, error) {
+	if excludeUserIDs == nil {
+		excludeUserIDs = []string{}
+	}
+
+	query := `
+		UPDATE "user" SET is_active = false
+		WHERE team_name = $1 AND is_active = true AND NOT (user_id = ANY($2))
+		RETURNING user_id
+	`
+	deactivated, err := r.queryUserIDs(ctx, query, teamName, pq.Array(excludeUserIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to mass deactivate users: %v", err)
+	}
+	return deactivated, nil
+}
 
-	if len(excludeUserIDs) == 0 {
-		query = `UPDATE "user" SET is_active = false WHERE team_name = $1 AND is_active = true`
-		args = []interface{}{teamName}
-	} else {
-		query = `UPDATE "user" SET is_active = false WHERE team_name = $1 AND is_active = true AND NOT (user_id = ANY($2))`
-		args = []interface{}{teamName, pq.Array(excludeUserIDs)}
+func (r *PostgresRepository) GetMassDeactivationTargets(ctx context.Context, teamName string, excludeUserIDs []string) ([]string, error) {
+	if excludeUserIDs == nil {
+		excludeUserIDs = []string{}
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	query := `
+		SELECT user_id FROM "user"
+		WHERE team_name = $1 AND is_active = true AND NOT (user_id = ANY($2))
+		ORDER BY user_id
+	`
+	targets, err := r.queryUserIDs(ctx, query, teamName, pq.Array(excludeUserIDs))
 	if err != nil {
-		return 0, fmt.Errorf("failed to mass deactivate users: %v", err)
+		return nil, fmt.Errorf("failed to get mass deactivation targets: %v", err)
 	}
+	return targets, nil
+}
 
-	rowsAffected, err := result.RowsAffected()
+func (r *PostgresRepository) queryUserIDs(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := r.q.QueryContext(ctx, query, args...)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get rows affected: %v", err)
+		return nil, err
 	}
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			fmt.Printf("failed to close rows: %v", err)
+		}
+	}(rows)
 
-	return int(rowsAffected), nil
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("scan error: %v", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
 }
 
-func (r *PostgresRepository) GetOpenPRsWithReviewers(ctx context.Context, teamName string) ([]models.OpenPRInfo, error) {
+func (r *PostgresRepository) GetOpenPRsByReviewers(ctx context.Context, reviewerIDs []string) ([]models.OpenPRInfo, error) {
 	query := `
-		SELECT 
-			pr.pull_request_id,
-			pr.assigned_reviewers,
-			u.user_id as author_id
+		SELECT pr.pull_request_id, COALESCE(pr.author_id, ''), pr.required_tags
 		FROM pull_request pr
-		JOIN "user" u ON pr.author_id = u.user_id
-		WHERE pr.status = 'OPEN' 
-		AND u.team_name = $1
+		WHERE pr.status = 'OPEN'
+		AND EXISTS (
+			SELECT 1 FROM pr_reviewer r
+			WHERE r.pull_request_id = pr.pull_request_id AND r.user_id = ANY($1)
+		)
+		ORDER BY pr.pull_request_id
 	`
+	return r.queryOpenPRs(ctx, query, pq.Array(reviewerIDs))
+}
+
+func (r *PostgresRepository) GetOpenPRsByAuthors(ctx context.Context, authorIDs []string) ([]models.OpenPRInfo, error) {
+	query := `
+		SELECT pr.pull_request_id, pr.author_id, pr.required_tags
+		FROM pull_request pr
+		WHERE pr.status IN ('OPEN', 'DRAFT') AND pr.author_id = ANY($1)
+		ORDER BY pr.pull_request_id
+	`
+	return r.queryOpenPRs(ctx, query, pq.Array(authorIDs))
+}
 
-	rows, err := r.db.QueryContext(ctx, query, teamName)
+// queryOpenPRs выполняет запрос, возвращающий pull_request_id, author_id и required_tags, и дополняет PR списками ревьюверов
+// и их запасными командами
+func (r *PostgresRepository) queryOpenPRs(ctx context.Context, query string, args ...interface{}) ([]models.OpenPRInfo, error) {
+	rows, err := r.q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get open PRs: %v", err)
 	}
@@ -56,58 +104,52 @@ func (r *PostgresRepository) GetOpenPRsWithReviewers(ctx context.Context, teamNa
 		}
 	}(rows)
 
-	var prs []models.OpenPRInfo
+	var prs []dto.PullRequest
 	for rows.Next() {
-		var pr models.OpenPRInfo
-		var reviewers []string
-
-		if err := rows.Scan(&pr.PRID, pq.Array(&reviewers), &pr.AuthorID); err != nil {
+		var pr dto.PullRequest
+		if err := rows.Scan(&pr.PullRequestID, &pr.AuthorID, pq.Array(&pr.RequiredTags)); err != nil {
 			return nil, fmt.Errorf("scan error: %v", err)
 		}
-
-		pr.AssignedReviewers = reviewers
 		prs = append(prs, pr)
 	}
-
-	return prs, nil
-}
-
-func (r *PostgresRepository) UpdatePRReviewersBatch(ctx context.Context, updates []models.PRReviewersUpdate) error {
-	tx, err := r.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %v", err)
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
-	defer func(tx *sql.Tx) {
-		err := tx.Rollback()
-		if err != nil {
-			fmt.Printf("failed to rollback transaction: %v", err)
-		}
-	}(tx)
 
-	stmt, err := tx.PrepareContext(ctx, `
-		UPDATE pull_request 
-		SET assigned_reviewers = $1 
-		WHERE pull_request_id = $2
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %v", err)
+	if err := r.loadPRReviewers(ctx, prs); err != nil {
+		return nil, err
 	}
-	defer func(stmt *sql.Stmt) {
-		err := stmt.Close()
-		if err != nil {
-			fmt.Printf("failed to close statement: %v", err)
-		}
-	}(stmt)
 
-	for _, update := range updates {
-		if _, err := stmt.ExecContext(ctx, pq.Array(update.Reviewers), update.PRID); err != nil {
-			return fmt.Errorf("failed to update PR %s: %v", update.PRID, err)
+	openPRs := make([]models.OpenPRInfo, len(prs))
+	for i, pr := range prs {
+		openPRs[i] = models.OpenPRInfo{
+			PRID:              pr.PullRequestID,
+			AuthorID:          pr.AuthorID,
+			AssignedReviewers: pr.AssignedReviewers,
+			FallbackReviewers: pr.FallbackReviewers,
+			RequiredTags:      pr.RequiredTags,
 		}
 	}
+	return openPRs, nil
+}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %v", err)
+func (r *PostgresRepository) UpdatePRReviewersBatch(ctx context.Context, updates []models.PRReviewersUpdate, assignedBy string) error {
+	if len(updates) == 0 {
+		return nil
 	}
 
-	return nil
+	return r.withTx(ctx, func(tx *PostgresRepository) error {
+		for _, update := range updates {
+			if err := tx.setPRReviewers(ctx, update.PRID, update.Reviewers, assignedBy); err != nil {
+				return fmt.Errorf("failed to update PR %s: %v", update.PRID, err)
+			}
+			if err := tx.SetReviewerFallbackTeams(ctx, update.PRID, update.FallbackTeams); err != nil {
+				return err
+			}
+			if err := tx.SetReviewerExplanations(ctx, update.PRID, update.Explanations); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
@@ -11,14 +11,44 @@ func RegisterRoutes(e *echo.Echo, handler *handlers.Handler) {
 
 	e.POST("/team/add", handler.AddTeam)
 	e.GET("/team/get", handler.GetTeam)
+	e.GET("/team/settings", handler.GetTeamSettings)
+	e.POST("/team/settings", handler.UpdateTeamSettings)
+	e.GET("/teams", handler.ListTeams)
+	e.PATCH("/team", handler.UpdateTeamMembers)
+	e.POST("/team/rename", handler.RenameTeam)
+	e.DELETE("/team", handler.DeleteTeam)
 
+	e.GET("/users", handler.ListUsers)
+	e.GET("/users/get", handler.GetUser)
+	e.PATCH("/users", handler.UpdateUser)
+	e.DELETE("/users", handler.DeleteUser)
 	e.POST("/users/setIsActive", handler.SetUserActive)
+	e.POST("/users/setMaxOpenReviews", handler.SetUserMaxOpenReviews)
+	e.POST("/users/setTags", handler.SetUserTags)
 	e.GET("/users/getReview", handler.GetUserReviews)
+	e.POST("/users/move", handler.MoveUser)
+	e.POST("/users/leave", handler.CreateUserLeave)
+	e.GET("/users/leave", handler.GetUserLeaves)
+	e.DELETE("/users/leave", handler.DeleteUserLeave)
 	e.POST("/users/massDeactivate", handler.MassDeactivateTeamUsers)
+	e.POST("/users/massActivate", handler.MassActivateUsers)
 
 	e.POST("/pullRequest/create", handler.CreatePR)
+	e.GET("/pullRequest/get", handler.GetPR)
 	e.POST("/pullRequest/merge", handler.MergePR)
+	e.POST("/pullRequest/close", handler.ClosePR)
+	e.POST("/pullRequest/reopen", handler.ReopenPR)
+	e.POST("/pullRequest/markReady", handler.MarkPRReady)
 	e.POST("/pullRequest/reassign", handler.ReassignReviewer)
+	e.POST("/pullRequest/review", handler.SubmitReview)
+
+	e.GET("/tags", handler.ListTags)
+
+	e.GET("/codeowners", handler.GetCodeOwners)
+	e.PUT("/codeowners", handler.ReplaceCodeOwners)
+	e.POST("/codeowners/import", handler.ImportCodeOwners)
+
+	e.GET("/jobs/:job_id", handler.GetJob)
 
 	e.GET("/stats/users", handler.GetUserReviewStats)
 	e.GET("/stats/prs", handler.GetPRReviewStats)
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+	"regexp"
+	"strings"
+)
+
+func (s *ServiceImpl) GetCodeOwners(ctx context.Context) (*dto.CodeOwnersResponse, error) {
+	rules, err := s.repo.GetCodeOwnerRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []models.CodeOwnerRule{}
+	}
+	return &dto.CodeOwnersResponse{Rules: rules}, nil
+}
+
+func (s *ServiceImpl) ReplaceCodeOwners(ctx context.Context, req dto.CodeOwnersRequest) (*dto.CodeOwnersResponse, error) {
+	rules, err := s.validateCodeOwnerRules(ctx, req.Rules)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceCodeOwnerRules(ctx, rules); err != nil {
+		return nil, err
+	}
+	return &dto.CodeOwnersResponse{Rules: rules}, nil
+}
+
+// ImportCodeOwners заменяет реестр правилами из файла CODEOWNERS
+func (s *ServiceImpl) ImportCodeOwners(ctx context.Context, content string) (*dto.CodeOwnersResponse, error) {
+	rules, err := parseCodeOwners(content)
+	if err != nil {
+		return nil, err
+	}
+	return s.ReplaceCodeOwners(ctx, dto.CodeOwnersRequest{Rules: rules})
+}
+
+// validateCodeOwnerRules проверяет шаблоны и существование владельцев, убирая повторы внутри правила
+func (s *ServiceImpl) validateCodeOwnerRules(ctx context.Context, rules []models.CodeOwnerRule) ([]models.CodeOwnerRule, error) {
+	normalized := make([]models.CodeOwnerRule, 0, len(rules))
+	for _, rule := range rules {
+		if _, err := compileOwnerPattern(rule.Pattern); err != nil {
+			return nil, err
+		}
+		users := make([]string, 0, len(rule.Users))
+		for _, userID := range rule.Users {
+			if contains(users, userID) {
+				continue
+			}
+			if _, err := s.repo.GetUser(ctx, userID); err != nil {
+				if err.Error() == "user not found" {
+					return nil, fmt.Errorf("%w: unknown user %s", errors.ErrInvalidCodeOwners, userID)
+				}
+				return nil, err
+			}
+			users = append(users, userID)
+		}
+
+		teams := make([]string, 0, len(rule.Teams))
+		for _, teamName := range rule.Teams {
+			if contains(teams, teamName) {
+				continue
+			}
+			exists, err := s.repo.TeamExists(ctx, teamName)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				return nil, fmt.Errorf("%w: unknown team %s", errors.ErrInvalidCodeOwners, teamName)
+			}
+			teams = append(teams, teamName)
+		}
+
+		normalized = append(normalized, models.CodeOwnerRule{Pattern: rule.Pattern, Users: users, Teams: teams})
+	}
+	return normalized, nil
+}
+
+// parseCodeOwners разбирает файл CODEOWNERS: @user - пользователь, @org/team - команда team.
+// Секции и владельцы-адреса почты не поддерживаются
+func parseCodeOwners(content string) ([]models.CodeOwnerRule, error) {
+	rules := []models.CodeOwnerRule{}
+	for i, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			return nil, fmt.Errorf("%w: line %d: sections are not supported", errors.ErrInvalidCodeOwners, i+1)
+		}
+
+		rule := models.CodeOwnerRule{Pattern: fields[0], Users: []string{}, Teams: []string{}}
+		for _, owner := range fields[1:] {
+			name, ok := strings.CutPrefix(owner, "@")
+			if !ok || name == "" {
+				return nil, fmt.Errorf("%w: line %d: owner %s must be @user or @org/team", errors.ErrInvalidCodeOwners, i+1, owner)
+			}
+			if slash := strings.LastIndex(name, "/"); slash >= 0 {
+				rule.Teams = append(rule.Teams, name[slash+1:])
+			} else {
+				rule.Users = append(rule.Users, name)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// compileOwnerPattern переводит шаблон CODEOWNERS в регулярное выражение.
+// Шаблон без "/" в начале или середине совпадает на любой глубине, шаблон на "/" - только с каталогом,
+// "*" и "?" не пересекают "/", "**" - пересекают
+func compileOwnerPattern(pattern string) (*regexp.Regexp, error) {
+	p := strings.TrimPrefix(pattern, "/")
+	anchored := p != pattern
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	if p == "" {
+		return nil, fmt.Errorf("%w: empty pattern", errors.ErrInvalidCodeOwners)
+	}
+	if strings.Contains(p, "/") {
+		anchored = true
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 3
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i += 2
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+			i++
+		case p[i] == '?':
+			b.WriteString("[^/]")
+			i++
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+			i++
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.*")
+	case !strings.HasSuffix(p, "*"):
+		// Шаблон без завершающей звёздочки может означать каталог
+		b.WriteString("(?:/.*)?")
+	}
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid pattern %s", errors.ErrInvalidCodeOwners, pattern)
+	}
+	return re, nil
+}
+
+// touchedCodeOwnerRules возвращает правила, которым принадлежит хотя бы один файл, в порядке реестра.
+// Как в CODEOWNERS, файл принадлежит последнему совпавшему правилу
+func touchedCodeOwnerRules(rules []models.CodeOwnerRule, changedFiles []string) ([]models.CodeOwnerRule, error) {
+	patterns := make([]*regexp.Regexp, len(rules))
+	for i, rule := range rules {
+		re, err := compileOwnerPattern(rule.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		patterns[i] = re
+	}
+
+	touched := make([]bool, len(rules))
+	for _, file := range changedFiles {
+		file = strings.TrimPrefix(file, "/")
+		for i := len(rules) - 1; i >= 0; i-- {
+			if patterns[i].MatchString(file) {
+				touched[i] = true
+				break
+			}
+		}
+	}
+
+	var areas []models.CodeOwnerRule
+	for i, rule := range rules {
+		if touched[i] {
+			areas = append(areas, rule)
+		}
+	}
+	return areas, nil
+}
+
+// selectCodeOwners назначает по одному владельцу на каждую затронутую область, если её ещё не покрывает
+// владелец, выбранный для другой области. Среди владельцев выбирается наименее загруженный с учётом его лимита,
+// предпочитая совпадающих по требуемым тегам
+func (s *ServiceImpl) selectCodeOwners(ctx context.Context, target reviewTarget, changedFiles []string) (*reviewerSelection, error) {
+	selection := &reviewerSelection{Reviewers: []string{}}
+	if len(changedFiles) == 0 {
+		return selection, nil
+	}
+
+	rules, err := s.repo.GetCodeOwnerRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	areas, err := touchedCodeOwnerRules(rules, changedFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	ownerTeams := map[string]string{}
+	teamSettings := map[string]*models.TeamSettings{}
+	for _, area := range areas {
+		// Правило без владельцев, как в CODEOWNERS, снимает владение с файлов
+		if len(area.Users) == 0 && len(area.Teams) == 0 {
+			continue
+		}
+
+		covered := false
+		for _, reviewerID := range selection.Reviewers {
+			if contains(area.Users, reviewerID) || contains(area.Teams, ownerTeams[reviewerID]) {
+				covered = true
+				break
+			}
+		}
+		if covered {
+			continue
+		}
+
+		owners, err := s.repo.GetUserAvailability(ctx, area.Users, area.Teams)
+		if err != nil {
+			return nil, err
+		}
+		available, exclusions := availableMembers(owners)
+		candidates, err := s.ownerCandidates(ctx, available, teamSettings)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := newCandidatePool(StrategyLeastLoaded, "", candidates, target.AuthorID, selection.Reviewers, exclusions)
+		picked, err := selectPreferred(ctx, s.strategies[StrategyLeastLoaded], "", pool.Candidates, target.RequiredTags, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(picked) == 0 {
+			selection.UncoveredPatterns = append(selection.UncoveredPatterns, area.Pattern)
+			continue
+		}
+
+		explanation := pool.explain(picked[0], target.RequiredTags)
+		explanation.CodeOwnerPattern = area.Pattern
+		for _, owner := range available {
+			if owner.UserID == picked[0] {
+				ownerTeams[owner.UserID] = owner.TeamName
+				explanation.TeamName = owner.TeamName
+			}
+		}
+
+		selection.add(picked[0], "")
+		selection.explain(picked[0], explanation)
+		if selection.CodeOwners == nil {
+			selection.CodeOwners = map[string]string{}
+		}
+		selection.CodeOwners[picked[0]] = area.Pattern
+	}
+	return selection, nil
+}
+
+// ownerCandidates собирает кандидатов из владельцев разных команд, беря лимит открытых ревью из настроек команды каждого
+func (s *ServiceImpl) ownerCandidates(ctx context.Context, owners []models.User, teamSettings map[string]*models.TeamSettings) ([]models.ReviewerCandidate, error) {
+	userIDs := make([]string, len(owners))
+	for i, owner := range owners {
+		userIDs[i] = owner.UserID
+	}
+	openReviews, err := s.repo.GetOpenReviewCounts(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]models.ReviewerCandidate, len(owners))
+	for i, owner := range owners {
+		settings, ok := teamSettings[owner.TeamName]
+		if !ok {
+			settings, err = s.GetTeamSettings(ctx, owner.TeamName)
+			if err != nil {
+				return nil, err
+			}
+			teamSettings[owner.TeamName] = settings
+		}
+		candidates[i] = models.ReviewerCandidate{
+			UserID:         owner.UserID,
+			OpenReviews:    openReviews[owner.UserID],
+			MaxOpenReviews: reviewCapacity(settings, owner),
+			Tags:           owner.Tags,
+		}
+	}
+	return candidates, nil
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	models "pr_task/internal/model"
+	"pr_task/internal/repository"
+	"sync"
+	"time"
+)
+
+type JobRunnerConfig struct {
+	Workers      int
+	PollInterval time.Duration
+	// Lease - сколько задача считается занятой без продления. Пока задача выполняется, аренда продлевается
+	// каждую треть Lease. После истечения аренды задачу забирает другой исполнитель, так переживаются падения и перезапуски сервиса
+	Lease       time.Duration
+	MaxAttempts int
+}
+
+type jobHandler func(ctx context.Context, job *models.Job, progress ProgressFunc) (any, error)
+
+// JobRunner забирает задачи из таблицы job и выполняет их в пуле воркеров.
+// Массовые операции атомарны, поэтому прерванная задача при повторном запуске выполняется заново целиком.
+// Между опросами очереди воркеры передают открытые ревью пользователей, чей период отсутствия начался
+type JobRunner struct {
+	repo     repository.Repository
+	cfg      JobRunnerConfig
+	service  *ServiceImpl
+	handlers map[string]jobHandler
+}
+
+func NewJobRunner(repo repository.Repository, cfg JobRunnerConfig) *JobRunner {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = 5 * time.Minute
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+
+	service := &ServiceImpl{
+		repo:       repo,
+		strategies: NewReviewerSelectionStrategies(repo),
+	}
+
+	return &JobRunner{
+		repo:    repo,
+		cfg:     cfg,
+		service: service,
+		handlers: map[string]jobHandler{
+			JobTypeMassDeactivation: service.runMassDeactivationJob,
+		},
+	}
+}
+
+// Run запускает воркеры и блокируется до отмены ctx
+func (r *JobRunner) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < r.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (r *JobRunner) work(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for r.RunNext(ctx) {
+		}
+		if _, err := r.ReassignStartedLeaves(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("job runner: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunNext выполняет одну задачу из очереди и сообщает, была ли задача
+func (r *JobRunner) RunNext(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if _, err := r.repo.FailExhaustedJobs(ctx, r.cfg.MaxAttempts); err != nil {
+		log.Printf("job runner: %v", err)
+	}
+
+	job, err := r.repo.ClaimJob(ctx, r.cfg.Lease, r.cfg.MaxAttempts)
+	if err != nil {
+		log.Printf("job runner: %v", err)
+		return false
+	}
+	if job == nil {
+		return false
+	}
+
+	r.execute(ctx, job)
+	return true
+}
+
+// ReassignStartedLeaves передаёт открытые ревью по начавшимся периодам отсутствия с auto_reassign
+// и возвращает число обработанных периодов
+func (r *JobRunner) ReassignStartedLeaves(ctx context.Context) (int, error) {
+	if ctx.Err() != nil {
+		return 0, nil
+	}
+	return r.service.reassignStartedLeaves(ctx)
+}
+
+func (r *JobRunner) execute(ctx context.Context, job *models.Job) {
+	handler, ok := r.handlers[job.Type]
+	if !ok {
+		r.fail(ctx, job, fmt.Errorf("unknown job type %q", job.Type))
+		return
+	}
+
+	heartbeatCtx, stopHeartbeat := context.WithCancel(ctx)
+	go r.heartbeat(heartbeatCtx, job)
+	result, err := handler(ctx, job, r.progress(ctx, job))
+	stopHeartbeat()
+	if err != nil {
+		if ctx.Err() != nil {
+			// Сервис останавливается: задачу заберёт другой воркер после истечения аренды
+			return
+		}
+		r.fail(ctx, job, err)
+		return
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		r.fail(ctx, job, fmt.Errorf("failed to marshal job result: %v", err))
+		return
+	}
+	if err := r.repo.CompleteJob(ctx, job.JobID, job.Attempts, data); err != nil {
+		log.Printf("job runner: job %s: %v", job.JobID, err)
+	}
+}
+
+// heartbeat продлевает аренду задачи, пока не отменён ctx, в том числе на этапах без прогресса
+func (r *JobRunner) heartbeat(ctx context.Context, job *models.Job) {
+	ticker := time.NewTicker(r.cfg.Lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.repo.ExtendJobLease(ctx, job.JobID, job.Attempts, r.cfg.Lease); err != nil && ctx.Err() == nil {
+				log.Printf("job runner: job %s: %v", job.JobID, err)
+			}
+		}
+	}
+}
+
+// progress сохраняет прогресс не чаще раза в секунду и продлевает аренду задачи
+func (r *JobRunner) progress(ctx context.Context, job *models.Job) ProgressFunc {
+	var last time.Time
+	return func(done, total int) {
+		if done < total && time.Since(last) < time.Second {
+			return
+		}
+		last = time.Now()
+
+		if err := r.repo.UpdateJobProgress(ctx, job.JobID, job.Attempts, done, total, r.cfg.Lease); err != nil {
+			log.Printf("job runner: job %s: %v", job.JobID, err)
+		}
+	}
+}
+
+func (r *JobRunner) fail(ctx context.Context, job *models.Job, cause error) {
+	log.Printf("job runner: job %s failed: %v", job.JobID, cause)
+	if err := r.repo.FailJob(ctx, job.JobID, job.Attempts, cause.Error()); err != nil {
+		log.Printf("job runner: job %s: %v", job.JobID, err)
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+)
+
+const (
+	JobStatusPending   = "PENDING"
+	JobStatusRunning   = "RUNNING"
+	JobStatusSucceeded = "SUCCEEDED"
+	JobStatusFailed    = "FAILED"
+
+	JobTypeMassDeactivation = "mass_deactivation"
+)
+
+// EnqueueMassDeactivation ставит массовую деактивацию в очередь фоновых задач.
+// Команда проверяется сразу, чтобы клиент получил 404, а не упавшую задачу
+func (s *ServiceImpl) EnqueueMassDeactivation(ctx context.Context, req dto.MassDeactivationRequest) (*models.Job, error) {
+	if _, err := s.GetTeamSettings(ctx, req.TeamName); err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job payload: %v", err)
+	}
+
+	job := &models.Job{Type: JobTypeMassDeactivation, Payload: payload}
+	if err := s.repo.CreateJob(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *ServiceImpl) GetJob(ctx context.Context, jobID string) (*models.Job, error) {
+	job, err := s.repo.GetJob(ctx, jobID)
+	if err != nil {
+		if err.Error() == "job not found" {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *ServiceImpl) runMassDeactivationJob(ctx context.Context, job *models.Job, progress ProgressFunc) (any, error) {
+	var req dto.MassDeactivationRequest
+	if err := json.Unmarshal(job.Payload, &req); err != nil {
+		return nil, fmt.Errorf("invalid job payload: %v", err)
+	}
+	req.DryRun = false
+
+	return s.massDeactivateTeamUsers(ctx, req, progress)
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+	"strings"
+	"time"
+)
+
+const leaveDateLayout = "2006-01-02"
+
+// CreateUserLeave добавляет период отсутствия пользователя. Пересекающиеся периоды запрещены.
+// Если период с auto_reassign уже начался, открытые ревью пользователя передаются сразу
+func (s *ServiceImpl) CreateUserLeave(ctx context.Context, req dto.CreateUserLeaveRequest) (*dto.UserLeaveResponse, error) {
+	startsOn, err := time.Parse(leaveDateLayout, req.StartsOn)
+	if err != nil {
+		return nil, errors.ErrInvalidLeavePeriod
+	}
+	endsOn, err := time.Parse(leaveDateLayout, req.EndsOn)
+	if err != nil || endsOn.Before(startsOn) {
+		return nil, errors.ErrInvalidLeavePeriod
+	}
+
+	response := &dto.UserLeaveResponse{}
+	err = s.withTx(ctx, func(tx *ServiceImpl) error {
+		if _, err := tx.GetUser(ctx, req.UserID); err != nil {
+			return err
+		}
+
+		overlaps, err := tx.repo.HasOverlappingLeave(ctx, req.UserID, req.StartsOn, req.EndsOn)
+		if err != nil {
+			return err
+		}
+		if overlaps {
+			return errors.ErrLeaveOverlap
+		}
+
+		leave := &models.UserLeave{
+			UserID:       req.UserID,
+			StartsOn:     req.StartsOn,
+			EndsOn:       req.EndsOn,
+			Reason:       req.Reason,
+			AutoReassign: req.AutoReassign,
+		}
+		// Проверка выше даёт понятную ошибку, а параллельную вставку пересекающегося периода отклоняет ограничение в БД
+		if err := tx.repo.CreateUserLeave(ctx, leave); err != nil {
+			if err.Error() == "leave overlaps" {
+				return errors.ErrLeaveOverlap
+			}
+			return err
+		}
+		response.Leave = leave
+
+		if !leave.AutoReassign {
+			return nil
+		}
+		started, err := tx.repo.ClaimStartedLeaves(ctx, req.UserID)
+		if err != nil {
+			return err
+		}
+		for _, claimed := range started {
+			handoff, err := tx.reassignLeaveReviews(ctx, claimed.UserID)
+			if err != nil {
+				return err
+			}
+			response.Reassignments = append(response.Reassignments, handoff.Reassignments...)
+			response.NotReassignedPRs = append(response.NotReassignedPRs, handoff.NotReassignedPRs...)
+			if claimed.LeaveID == leave.LeaveID {
+				response.Leave = &claimed
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *ServiceImpl) GetUserLeaves(ctx context.Context, userID string) (*dto.UserLeavesResponse, error) {
+	if _, err := s.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	leaves, err := s.repo.GetUserLeaves(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.UserLeavesResponse{
+		UserID: userID,
+		Leaves: leaves,
+	}, nil
+}
+
+// DeleteUserLeave удаляет период отсутствия. Переданные ранее ревью пользователю не возвращаются
+func (s *ServiceImpl) DeleteUserLeave(ctx context.Context, leaveID string) (*models.UserLeave, error) {
+	leave, err := s.repo.DeleteUserLeave(ctx, leaveID)
+	if err != nil {
+		if err.Error() == "leave not found" {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return leave, nil
+}
+
+// reassignStartedLeaves передаёт открытые ревью пользователей, чей период с auto_reassign начался,
+// и возвращает число обработанных периодов. Периоды каждого пользователя отмечаются и обрабатываются в своей транзакции:
+// ошибка по одному пользователю не мешает остальным, его периоды остаются неотмеченными и повторяются при следующем опросе
+func (s *ServiceImpl) reassignStartedLeaves(ctx context.Context) (int, error) {
+	started, err := s.repo.GetStartedLeaves(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var userIDs []string
+	for _, leave := range started {
+		if !contains(userIDs, leave.UserID) {
+			userIDs = append(userIDs, leave.UserID)
+		}
+	}
+
+	var processed int
+	var failures []string
+	for _, userID := range userIDs {
+		var claimedCount int
+		err := s.withTx(ctx, func(tx *ServiceImpl) error {
+			claimed, err := tx.repo.ClaimStartedLeaves(ctx, userID)
+			if err != nil {
+				return err
+			}
+			// Периоды уже обработал другой исполнитель
+			if len(claimed) == 0 {
+				return nil
+			}
+			if _, err := tx.reassignLeaveReviews(ctx, userID); err != nil {
+				return err
+			}
+			claimedCount = len(claimed)
+			return nil
+		})
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("user %s: %v", userID, err))
+			continue
+		}
+		processed += claimedCount
+	}
+
+	if len(failures) > 0 {
+		return processed, fmt.Errorf("failed to reassign reviews of started leaves: %s", strings.Join(failures, "; "))
+	}
+	return processed, nil
+}
+
+// reassignLeaveReviews передаёт открытые ревью отсутствующего пользователя участникам его команды.
+// Должен вызываться внутри транзакции
+func (s *ServiceImpl) reassignLeaveReviews(ctx context.Context, userID string) (*models.ReviewHandoff, error) {
+	user, err := s.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := s.GetTeamSettings(ctx, user.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reassignOpenReviews(ctx, userID, settings, AssignedByLeave)
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+	"slices"
+)
+
+func (s *ServiceImpl) MassActivateUsers(ctx context.Context, req dto.MassActivationRequest) (*dto.MassActivationResponse, error) {
+	if req.OperationID != "" {
+		return s.undoMassDeactivation(ctx, req.OperationID, req.RestoreReviewers)
+	}
+
+	if _, err := s.GetTeamSettings(ctx, req.TeamName); err != nil {
+		return nil, err
+	}
+
+	activated, err := s.repo.MassActivateUsers(ctx, req.TeamName, req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MassActivationResponse{
+		ActivatedUsers:   len(activated),
+		ActivatedUserIDs: activated,
+	}, nil
+}
+
+// undoMassDeactivation активирует ровно тех пользователей, которых деактивировала операция,
+// и при restoreReviewers возвращает прежних ревьюверов открытым PR, чей состав с тех пор не менялся
+func (s *ServiceImpl) undoMassDeactivation(ctx context.Context, operationID string, restoreReviewers bool) (*dto.MassActivationResponse, error) {
+	response := &dto.MassActivationResponse{OperationID: operationID}
+
+	err := s.withTx(ctx, func(tx *ServiceImpl) error {
+		op, err := tx.repo.GetMassDeactivationOperation(ctx, operationID)
+		if err != nil {
+			if err.Error() == "operation not found" {
+				return errors.ErrNotFound
+			}
+			return err
+		}
+		if op.UndoneAt != nil {
+			return errors.ErrOperationUndone
+		}
+
+		activated, err := tx.repo.MassActivateUsers(ctx, "", op.DeactivatedUserIDs)
+		if err != nil {
+			return err
+		}
+		response.ActivatedUsers = len(activated)
+		response.ActivatedUserIDs = activated
+
+		if restoreReviewers {
+			var updates []models.PRReviewersUpdate
+			for _, change := range op.PRChanges {
+				pr, err := tx.repo.GetPR(ctx, change.PRID)
+				if err != nil {
+					if err.Error() == "PR not found" {
+						response.SkippedPRs = append(response.SkippedPRs, change.PRID)
+						continue
+					}
+					return err
+				}
+
+				if pr.Status != "OPEN" || !sameReviewers(pr.AssignedReviewers, change.After) {
+					response.SkippedPRs = append(response.SkippedPRs, change.PRID)
+					continue
+				}
+
+				updates = append(updates, models.PRReviewersUpdate{PRID: change.PRID, Reviewers: change.Before})
+				response.RestoredPRs = append(response.RestoredPRs, models.PRReviewersChange{
+					PRID:   change.PRID,
+					Before: pr.AssignedReviewers,
+					After:  change.Before,
+				})
+			}
+
+			if err := tx.repo.UpdatePRReviewersBatch(ctx, updates, AssignedByUndo); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.repo.MarkMassDeactivationUndone(ctx, operationID); err != nil {
+			if err.Error() == "operation already undone" {
+				return errors.ErrOperationUndone
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func sameReviewers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, reviewer := range a {
+		if !slices.Contains(b, reviewer) {
+			return false
+		}
+	}
+	return true
+}
@@ -4,124 +4,314 @@ import (
 	"context"
 	"fmt"
 	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
 	models "pr_task/internal/model"
 	"time"
 )
 
-func (s *ServiceImpl) MassDeactivateTeamUsers(ctx context.Context, teamName string, excludeUserIDs []string) (*dto.MassDeactivationResponse, error) {
+// ProgressFunc сообщает, сколько элементов из total уже обработано
+type ProgressFunc func(done, total int)
+
+func (s *ServiceImpl) MassDeactivateTeamUsers(ctx context.Context, req dto.MassDeactivationRequest) (*dto.MassDeactivationResponse, error) {
+	return s.massDeactivateTeamUsers(ctx, req, nil)
+}
+
+func (s *ServiceImpl) massDeactivateTeamUsers(ctx context.Context, req dto.MassDeactivationRequest, progress ProgressFunc) (*dto.MassDeactivationResponse, error) {
 	startTime := time.Now()
 
-	_, err := s.repo.GetTeam(ctx, teamName)
+	settings, err := s.GetTeamSettings(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Пробный запуск вычисляет план в транзакции только для чтения и ничего не блокирует
+	withTx := s.withTx
+	if req.DryRun {
+		withTx = s.withPreviewTx
+	}
+
+	var result *models.MassDeactivationResult
+	err = withTx(ctx, func(tx *ServiceImpl) error {
+		result, err = tx.massDeactivate(ctx, settings, req.ExcludeUserIDs, req.DryRun, progress)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errors.ErrMassDeactivationFailed, err)
+	}
+
+	response := &dto.MassDeactivationResponse{
+		DeactivatedUsers: result.DeactivatedUsers,
+		UpdatedPRs:       result.UpdatedPRs,
+		UnderstaffedPRs:  result.UnderstaffedPRs,
+		PRChanges:        result.PRChanges,
+		ProcessingTime:   time.Since(startTime).Milliseconds(),
+		DryRun:           req.DryRun,
+		OperationID:      result.OperationID,
+	}
+
+	if req.DryRun {
+		response.OperationID = ""
+		response.Plan = &dto.MassDeactivationPlan{
+			DeactivatedUserIDs: result.DeactivatedUserIDs,
+			PRChanges:          result.PRChanges,
+		}
+		response.PRChanges = nil
+	}
+
+	return response, nil
+}
+
+// massDeactivate деактивирует пользователей команды и заменяет их в открытых PR.
+// Должен вызываться внутри транзакции, чтобы любая ошибка откатывала операцию целиком.
+// С dryRun только вычисляет план: пользователи, ревьюверы и журнал операций не меняются
+func (s *ServiceImpl) massDeactivate(ctx context.Context, settings *models.TeamSettings, excludeUserIDs []string, dryRun bool, progress ProgressFunc) (*models.MassDeactivationResult, error) {
+	deactivate := s.repo.MassDeactivateUsers
+	if dryRun {
+		deactivate = s.repo.GetMassDeactivationTargets
+	}
+	deactivated, err := deactivate(ctx, settings.TeamName, excludeUserIDs)
 	if err != nil {
-		return nil, fmt.Errorf("team not found: %v", err)
+		return nil, err
 	}
 
-	deactivatedCount, err := s.repo.MassDeactivateUsers(ctx, teamName, excludeUserIDs)
+	result := &models.MassDeactivationResult{
+		DeactivatedUsers:   len(deactivated),
+		DeactivatedUserIDs: deactivated,
+	}
+	if len(deactivated) == 0 {
+		return result, nil
+	}
+
+	openPRs, err := s.repo.GetOpenPRsByReviewers(ctx, deactivated)
 	if err != nil {
-		return nil, fmt.Errorf("failed to deactivate users: %v", err)
+		return nil, err
 	}
 
-	if deactivatedCount == 0 {
-		return &dto.MassDeactivationResponse{
-			DeactivatedUsers: 0,
-			UpdatedPRs:       0,
-			FailedPRs:        []string{},
-			ProcessingTime:   time.Since(startTime).Milliseconds(),
-		}, nil
+	teams, err := s.massDeactivationTeams(ctx, settings, deactivated)
+	if err != nil {
+		return nil, err
 	}
 
-	openPRs, err := s.repo.GetOpenPRsWithReviewers(ctx, teamName)
+	updates, err := s.replaceDeactivatedReviewers(ctx, deactivated, openPRs, teams, result, progress)
 	if err != nil {
-		return &dto.MassDeactivationResponse{
-			DeactivatedUsers: deactivatedCount,
-			UpdatedPRs:       0,
-			FailedPRs:        []string{"all"},
-			ProcessingTime:   time.Since(startTime).Milliseconds(),
-		}, nil
+		return nil, err
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	if err := s.repo.UpdatePRReviewersBatch(ctx, updates, AssignedByMassDeactivation); err != nil {
+		return nil, err
 	}
 
-	updateResult := s.updateReviewersForOpenPRs(ctx, openPRs, excludeUserIDs)
+	op := &models.MassDeactivationOperation{
+		TeamName:           settings.TeamName,
+		DeactivatedUserIDs: deactivated,
+		PRChanges:          result.PRChanges,
+	}
+	if err := s.repo.CreateMassDeactivationOperation(ctx, op); err != nil {
+		return nil, err
+	}
+	result.OperationID = op.OperationID
 
-	processingTime := time.Since(startTime).Milliseconds()
+	return result, nil
+}
 
-	return &dto.MassDeactivationResponse{
-		DeactivatedUsers: deactivatedCount,
-		UpdatedPRs:       updateResult.UpdatedPRs,
-		FailedPRs:        updateResult.FailedPRs,
-		ProcessingTime:   processingTime,
-	}, nil
+// teamCandidates - доступные участники команды с нагрузкой, которую массовая операция обновляет по мере назначений,
+// и участники, исключённые для всех PR (неактивные и отсутствующие)
+type teamCandidates struct {
+	settings   *models.TeamSettings
+	candidates []models.ReviewerCandidate
+	exclusions map[string][]string
 }
 
-func (s *ServiceImpl) updateReviewersForOpenPRs(ctx context.Context, openPRs []models.OpenPRInfo, excludeUserIDs []string) *models.MassDeactivationResult {
-	if len(openPRs) == 0 {
-		return &models.MassDeactivationResult{UpdatedPRs: 0}
+// massDeactivationTeams собирает кандидатов команды и её запасных команд по порядку
+func (s *ServiceImpl) massDeactivationTeams(ctx context.Context, settings *models.TeamSettings, deactivated []string) ([]*teamCandidates, error) {
+	teamSettings := []*models.TeamSettings{settings}
+	for _, teamName := range settings.FallbackTeams {
+		fallbackSettings, err := s.GetTeamSettings(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+		teamSettings = append(teamSettings, fallbackSettings)
 	}
 
+	teams := make([]*teamCandidates, 0, len(teamSettings))
+	for _, ts := range teamSettings {
+		members, err := s.repo.GetUserAvailability(ctx, nil, []string{ts.TeamName})
+		if err != nil {
+			return nil, err
+		}
+
+		// В пробном запуске деактивируемые ещё активны в БД, поэтому исключаются здесь
+		available, exclusions := availableMembers(members)
+		available, exclusions = withoutDeactivated(available, exclusions, deactivated)
+		candidates, err := s.reviewerCandidates(ctx, ts, available)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, &teamCandidates{settings: ts, candidates: candidates, exclusions: exclusions})
+	}
+	return teams, nil
+}
+
+// replaceDeactivatedReviewers заменяет деактивированных ревьюверов открытых PR участниками команды teams[0],
+// а если их не хватает - участниками запасных команд по порядку. Число ревьюверов PR ограничивают настройки команды его автора
+func (s *ServiceImpl) replaceDeactivatedReviewers(ctx context.Context, deactivatedUserIDs []string, openPRs []models.OpenPRInfo, teams []*teamCandidates, result *models.MassDeactivationResult, progress ProgressFunc) ([]models.PRReviewersUpdate, error) {
 	var updates []models.PRReviewersUpdate
-	var failedPRs []string
+	authorTeams := map[string]*models.TeamSettings{teams[0].settings.TeamName: teams[0].settings}
 
-	for _, pr := range openPRs {
-		newReviewers := s.getUpdatedReviewers(pr.AssignedReviewers, excludeUserIDs, pr.AuthorID)
+	for i, pr := range openPRs {
+		settings, err := s.authorTeamSettings(ctx, pr.AuthorID, teams[0].settings, authorTeams)
+		if err != nil {
+			return nil, err
+		}
+
+		selection, err := s.getUpdatedReviewers(ctx, settings, pr, deactivatedUserIDs, teams)
+		if err != nil {
+			return nil, err
+		}
 
 		updates = append(updates, models.PRReviewersUpdate{
-			PRID:      pr.PRID,
-			Reviewers: newReviewers,
+			PRID:          pr.PRID,
+			Reviewers:     selection.Reviewers,
+			FallbackTeams: selection.FallbackTeams,
+			Explanations:  selection.Explanations,
 		})
+
+		result.PRChanges = append(result.PRChanges, models.PRReviewersChange{
+			PRID:          pr.PRID,
+			Before:        pr.AssignedReviewers,
+			After:         selection.Reviewers,
+			FallbackTeams: selection.FallbackTeams,
+			Explanations:  selection.Explanations,
+		})
+
+		if len(selection.Reviewers) < min(len(pr.AssignedReviewers), settings.MaxReviewers) {
+			result.UnderstaffedPRs = append(result.UnderstaffedPRs, pr.PRID)
+		}
+
+		if progress != nil {
+			progress(i+1, len(openPRs))
+		}
+	}
+
+	result.UpdatedPRs = len(updates)
+	return updates, nil
+}
+
+// authorTeamSettings возвращает настройки команды автора PR, запрошенные настройки кэшируются в cache.
+// Для удалённого автора используются настройки defaultSettings
+func (s *ServiceImpl) authorTeamSettings(ctx context.Context, authorID string, defaultSettings *models.TeamSettings, cache map[string]*models.TeamSettings) (*models.TeamSettings, error) {
+	if authorID == "" {
+		return defaultSettings, nil
 	}
 
-	if len(updates) > 0 {
-		if err := s.repo.UpdatePRReviewersBatch(ctx, updates); err != nil {
-			for _, update := range updates {
-				failedPRs = append(failedPRs, update.PRID)
-			}
-			return &models.MassDeactivationResult{
-				UpdatedPRs: 0,
-				FailedPRs:  failedPRs,
-			}
+	author, err := s.repo.GetUser(ctx, authorID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return defaultSettings, nil
 		}
+		return nil, err
 	}
 
-	return &models.MassDeactivationResult{
-		UpdatedPRs: len(updates),
-		FailedPRs:  failedPRs,
+	if settings, ok := cache[author.TeamName]; ok {
+		return settings, nil
+	}
+	settings, err := s.GetTeamSettings(ctx, author.TeamName)
+	if err != nil {
+		return nil, err
 	}
+	cache[author.TeamName] = settings
+	return settings, nil
 }
 
-func (s *ServiceImpl) getUpdatedReviewers(currentReviewers []string, availableUsers []string, authorID string) []string {
-	newReviewers := make([]string, 0, 2)
+// getUpdatedReviewers оставляет активных ревьюверов PR и заменяет деактивированных, пока число ревьюверов
+// не превышает max_reviewers команды автора, затем добирает до её min_reviewers. Оставшиеся ревьюверы не удаляются
+func (s *ServiceImpl) getUpdatedReviewers(ctx context.Context, settings *models.TeamSettings, pr models.OpenPRInfo, deactivatedUserIDs []string, teams []*teamCandidates) (*reviewerSelection, error) {
+	for _, team := range teams {
+		if err := s.applyRecentReviews(ctx, team.settings, openPRTarget(pr), team.candidates); err != nil {
+			return nil, err
+		}
+	}
+	selection := &reviewerSelection{Reviewers: make([]string, 0, len(pr.AssignedReviewers))}
 
-	for _, reviewer := range currentReviewers {
-		if reviewer == authorID {
+	replaced := 0
+	for _, reviewer := range pr.AssignedReviewers {
+		if reviewer == pr.AuthorID {
 			continue
 		}
 
-		if contains(availableUsers, reviewer) {
-			newReviewers = append(newReviewers, reviewer)
+		if contains(deactivatedUserIDs, reviewer) {
+			replaced++
 			continue
 		}
+		selection.add(reviewer, pr.FallbackReviewers[reviewer])
+	}
 
-		replacement := s.findAvailableReviewer(availableUsers, newReviewers, authorID)
-
-		fmt.Println(replacement)
-		if replacement != "" {
-			newReviewers = append(newReviewers, replacement)
+	if count := min(replaced, settings.MaxReviewers-len(selection.Reviewers)); count > 0 {
+		if err := s.pickReplacements(ctx, settings.TeamName, pr, teams, selection, count); err != nil {
+			return nil, err
 		}
 	}
 
-	if len(newReviewers) > 2 {
-		newReviewers = newReviewers[:2]
+	if len(selection.Reviewers) < settings.MinReviewers {
+		if err := s.pickReplacements(ctx, settings.TeamName, pr, teams, selection, settings.MinReviewers-len(selection.Reviewers)); err != nil {
+			return nil, err
+		}
 	}
 
-	return newReviewers
+	return selection, nil
 }
 
-func (s *ServiceImpl) findAvailableReviewer(availableUsers []string, currentReviewers []string, authorID string) string {
-	excludeUsers := append(currentReviewers, authorID)
+// pickReplacements добавляет в selection до count новых ревьюверов PR: сначала из команды teams[0], затем из запасных по порядку.
+// Ревьюверы не из authorTeam отмечаются запасными. Нагрузка выбранных сразу учитывается для следующих PR операции
+func (s *ServiceImpl) pickReplacements(ctx context.Context, authorTeam string, pr models.OpenPRInfo, teams []*teamCandidates, selection *reviewerSelection, count int) error {
+	for _, team := range teams {
+		if count <= 0 {
+			break
+		}
+
+		strategy := s.teamStrategy(team.settings)
+		excludeUsers := append(append([]string{}, pr.AssignedReviewers...), selection.Reviewers...)
+		pool := newCandidatePool(strategy.Name(), team.settings.TeamName, team.candidates, pr.AuthorID, excludeUsers, team.exclusions)
+		picked, err := selectPreferred(ctx, strategy, team.settings.TeamName, pool.Candidates, pr.RequiredTags, count)
+		if err != nil {
+			return err
+		}
+
+		fallbackTeam := ""
+		if team.settings.TeamName != authorTeam {
+			fallbackTeam = team.settings.TeamName
+		}
+		for _, reviewer := range picked {
+			selection.add(reviewer, fallbackTeam)
+			selection.explain(reviewer, pool.explain(reviewer, pr.RequiredTags))
+			addOpenReview(team.candidates, reviewer)
+		}
+		count -= len(picked)
+	}
+	return nil
+}
+
+// withoutDeactivated переносит деактивируемых участников из доступных в неактивные
+func withoutDeactivated(available []models.User, exclusions map[string][]string, deactivated []string) ([]models.User, map[string][]string) {
+	remaining := make([]models.User, 0, len(available))
+	for _, member := range available {
+		if contains(deactivated, member.UserID) {
+			exclusions[models.ExclusionInactive] = append(exclusions[models.ExclusionInactive], member.UserID)
+			continue
+		}
+		remaining = append(remaining, member)
+	}
+	return remaining, exclusions
+}
 
-	for _, user := range availableUsers {
-		if !contains(excludeUsers, user) {
-			return user
+func addOpenReview(candidates []models.ReviewerCandidate, userID string) {
+	for i := range candidates {
+		if candidates[i].UserID == userID {
+			candidates[i].OpenReviews++
 		}
 	}
-	return ""
 }
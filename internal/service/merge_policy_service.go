@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+)
+
+// unmetMergeConditions проверяет PR по политике мержа команды автора и возвращает невыполненные условия
+func (s *ServiceImpl) unmetMergeConditions(ctx context.Context, pr *dto.PullRequest) ([]string, error) {
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	settings, err := s.GetTeamSettings(ctx, author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	var unmet []string
+	var approvers []string
+	for _, review := range pr.Reviews {
+		switch review.State {
+		case ReviewStateApproved:
+			approvers = append(approvers, review.UserID)
+		case ReviewStateChangesRequested:
+			if settings.BlockOnChangesRequested {
+				unmet = append(unmet, fmt.Sprintf("changes requested by %s", review.UserID))
+			}
+		}
+	}
+
+	if len(approvers) < settings.RequiredApprovals {
+		unmet = append(unmet, fmt.Sprintf("%d of %d required approvals", len(approvers), settings.RequiredApprovals))
+	}
+
+	if settings.RequireLeadApproval {
+		approvedByLead := false
+		for _, userID := range approvers {
+			approver, err := s.repo.GetUser(ctx, userID)
+			if err != nil {
+				if err.Error() == "user not found" {
+					continue
+				}
+				return nil, err
+			}
+			if approver.IsLead && approver.TeamName == author.TeamName {
+				approvedByLead = true
+				break
+			}
+		}
+		if !approvedByLead {
+			unmet = append(unmet, "no approval from a team lead")
+		}
+	}
+
+	return unmet, nil
+}
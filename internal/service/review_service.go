@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+)
+
+const (
+	ReviewStatePending          = "PENDING"
+	ReviewStateApproved         = "APPROVED"
+	ReviewStateChangesRequested = "CHANGES_REQUESTED"
+	ReviewStateCommented        = "COMMENTED"
+)
+
+// Источники назначения ревьюверов, сохраняемые в pr_reviewer.assigned_by
+const (
+	AssignedByCreate           = "create"
+	AssignedByMarkReady        = "mark_ready"
+	AssignedByReopen           = "reopen"
+	AssignedByReassign         = "reassign"
+	AssignedByMassDeactivation = "mass_deactivation"
+	AssignedByUndo             = "undo_mass_deactivation"
+	AssignedByUserRemoval      = "user_removal"
+	AssignedByUserMove         = "user_move"
+	AssignedByLeave            = "leave"
+)
+
+// SubmitReview сохраняет вердикт назначенного ревьювера. Повторный вердикт заменяет предыдущий
+func (s *ServiceImpl) SubmitReview(ctx context.Context, req dto.SubmitReviewRequest) (*dto.PullRequest, error) {
+	switch req.State {
+	case ReviewStateApproved, ReviewStateChangesRequested, ReviewStateCommented:
+	default:
+		return nil, errors.ErrInvalidReviewState
+	}
+
+	pr, err := s.repo.GetPR(ctx, req.PullRequestID)
+	if err != nil {
+		if err.Error() == "PR not found" {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+
+	switch pr.Status {
+	case "MERGED":
+		return nil, errors.ErrPRMerged
+	case "CLOSED":
+		return nil, errors.ErrPRClosed
+	case "DRAFT":
+		return nil, errors.ErrPRDraft
+	}
+
+	if err := s.repo.SetReviewState(ctx, req.PullRequestID, req.UserID, req.State); err != nil {
+		if err.Error() == "reviewer not assigned" {
+			return nil, errors.ErrNotAssigned
+		}
+		return nil, err
+	}
+
+	return s.repo.GetPR(ctx, req.PullRequestID)
+}
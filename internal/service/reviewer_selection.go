@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"time"
+
+	"pr_task/internal/dto"
+	models "pr_task/internal/model"
+	"pr_task/internal/repository"
+)
+
+const (
+	StrategyRandom      = "random"
+	StrategyRoundRobin  = "round_robin"
+	StrategyLeastLoaded = "least_loaded"
+	StrategyWeighted    = "weighted"
+
+	DefaultStrategy = StrategyLeastLoaded
+)
+
+// ReviewerSelectionStrategy выбирает до count ревьюверов из списка кандидатов команды
+type ReviewerSelectionStrategy interface {
+	Name() string
+	Select(ctx context.Context, teamName string, candidates []models.ReviewerCandidate, count int) ([]string, error)
+}
+
+func NewReviewerSelectionStrategies(repo repository.Repository) map[string]ReviewerSelectionStrategy {
+	return newReviewerSelectionStrategies(&roundRobinStrategy{repo: repo})
+}
+
+// newPreviewStrategies возвращает стратегии для пробного запуска: round_robin сдвигает курсор только в памяти
+func newPreviewStrategies(repo repository.Repository) map[string]ReviewerSelectionStrategy {
+	return newReviewerSelectionStrategies(&roundRobinStrategy{repo: repo, preview: map[string]string{}})
+}
+
+func newReviewerSelectionStrategies(roundRobin *roundRobinStrategy) map[string]ReviewerSelectionStrategy {
+	strategies := []ReviewerSelectionStrategy{
+		&randomStrategy{},
+		roundRobin,
+		&leastLoadedStrategy{},
+		&weightedStrategy{},
+	}
+
+	registry := make(map[string]ReviewerSelectionStrategy, len(strategies))
+	for _, strategy := range strategies {
+		registry[strategy.Name()] = strategy
+	}
+	return registry
+}
+
+type randomStrategy struct{}
+
+func (st *randomStrategy) Name() string {
+	return StrategyRandom
+}
+
+func (st *randomStrategy) Select(_ context.Context, _ string, candidates []models.ReviewerCandidate, count int) ([]string, error) {
+	shuffled := shuffleCandidates(candidates)
+	return candidateIDs(shuffled[:min(len(shuffled), count)]), nil
+}
+
+// tieredStrategy - стратегия, которая выбирает сразу по всем уровням предпочтения (см. selectPreferred) за один вызов.
+// Нужна стратегиям с сохраняемым состоянием, чтобы одно назначение меняло его один раз
+type tieredStrategy interface {
+	SelectTiered(ctx context.Context, teamName string, tiers [][]models.ReviewerCandidate, count int) ([]string, error)
+}
+
+// roundRobinStrategy обходит состав команды по порядку user_id, начиная после сохранённого курсора ротации.
+// Курсор хранится в БД и сдвигается под блокировкой, поэтому параллельные запросы не выбирают одних и тех же ревьюверов
+type roundRobinStrategy struct {
+	repo repository.Repository
+	// preview - курсоры команд в пробном запуске: читаются из БД один раз и дальше сдвигаются только здесь
+	preview map[string]string
+}
+
+func (st *roundRobinStrategy) Name() string {
+	return StrategyRoundRobin
+}
+
+func (st *roundRobinStrategy) Select(ctx context.Context, teamName string, candidates []models.ReviewerCandidate, count int) ([]string, error) {
+	return st.SelectTiered(ctx, teamName, [][]models.ReviewerCandidate{candidates}, count)
+}
+
+// SelectTiered читает и сдвигает курсор один раз на назначение: каждый уровень обходится по порядку user_id
+// после одного и того же курсора, а курсор переходит на последнего выбранного
+func (st *roundRobinStrategy) SelectTiered(ctx context.Context, teamName string, tiers [][]models.ReviewerCandidate, count int) ([]string, error) {
+	ordered := make([][]models.ReviewerCandidate, 0, len(tiers))
+	total := 0
+	for _, tier := range tiers {
+		if len(tier) == 0 {
+			continue
+		}
+		sorted := make([]models.ReviewerCandidate, len(tier))
+		copy(sorted, tier)
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].UserID < sorted[j].UserID
+		})
+		ordered = append(ordered, sorted)
+		total += len(sorted)
+	}
+
+	count = min(total, count)
+	if count <= 0 {
+		return []string{}, nil
+	}
+
+	reviewers := make([]string, 0, count)
+	advance := func(cursor string) string {
+		reviewers = reviewers[:0]
+		for _, tier := range ordered {
+			start := sort.Search(len(tier), func(i int) bool {
+				return tier[i].UserID > cursor
+			})
+			for i := 0; i < len(tier) && len(reviewers) < count; i++ {
+				reviewers = append(reviewers, tier[(start+i)%len(tier)].UserID)
+			}
+		}
+		return reviewers[len(reviewers)-1]
+	}
+
+	if st.preview != nil {
+		cursor, ok := st.preview[teamName]
+		if !ok {
+			var err error
+			if cursor, err = st.repo.GetRotationCursor(ctx, teamName); err != nil {
+				return nil, err
+			}
+		}
+		st.preview[teamName] = advance(cursor)
+		return reviewers, nil
+	}
+
+	if err := st.repo.AdvanceRotationCursor(ctx, teamName, advance); err != nil {
+		return nil, err
+	}
+	return reviewers, nil
+}
+
+// leastLoadedStrategy выбирает кандидатов с наименьшим числом открытых ревью, при равной нагрузке порядок случайный
+type leastLoadedStrategy struct{}
+
+func (st *leastLoadedStrategy) Name() string {
+	return StrategyLeastLoaded
+}
+
+func (st *leastLoadedStrategy) Select(_ context.Context, _ string, candidates []models.ReviewerCandidate, count int) ([]string, error) {
+	shuffled := shuffleCandidates(candidates)
+
+	sort.SliceStable(shuffled, func(i, j int) bool {
+		return shuffled[i].OpenReviews < shuffled[j].OpenReviews
+	})
+
+	return candidateIDs(shuffled[:min(len(shuffled), count)]), nil
+}
+
+// weightedStrategy выбирает кандидатов случайно с весом 1/(1+открытые ревью)
+type weightedStrategy struct{}
+
+func (st *weightedStrategy) Name() string {
+	return StrategyWeighted
+}
+
+func (st *weightedStrategy) Select(_ context.Context, _ string, candidates []models.ReviewerCandidate, count int) ([]string, error) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	pool := make([]models.ReviewerCandidate, len(candidates))
+	copy(pool, candidates)
+
+	reviewers := make([]string, 0, min(len(pool), count))
+	for len(pool) > 0 && len(reviewers) < count {
+		var total float64
+		for _, candidate := range pool {
+			total += candidateWeight(candidate)
+		}
+
+		target := rng.Float64() * total
+		picked := len(pool) - 1
+		for i, candidate := range pool {
+			target -= candidateWeight(candidate)
+			if target < 0 {
+				picked = i
+				break
+			}
+		}
+
+		reviewers = append(reviewers, pool[picked].UserID)
+		pool = append(pool[:picked], pool[picked+1:]...)
+	}
+	return reviewers, nil
+}
+
+func candidateWeight(candidate models.ReviewerCandidate) float64 {
+	return 1 / float64(1+candidate.OpenReviews)
+}
+
+func shuffleCandidates(candidates []models.ReviewerCandidate) []models.ReviewerCandidate {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	shuffled := make([]models.ReviewerCandidate, len(candidates))
+	copy(shuffled, candidates)
+
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	return shuffled
+}
+
+func candidateIDs(candidates []models.ReviewerCandidate) []string {
+	ids := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.UserID
+	}
+	return ids
+}
+
+// reviewTarget - PR, для которого выбираются ревьюверы. Его требуемые теги и автор определяют порядок кандидатов
+type reviewTarget struct {
+	PRID         string
+	AuthorID     string
+	RequiredTags []string
+}
+
+func prTarget(pr *dto.PullRequest) reviewTarget {
+	return reviewTarget{PRID: pr.PullRequestID, AuthorID: pr.AuthorID, RequiredTags: pr.RequiredTags}
+}
+
+func openPRTarget(pr models.OpenPRInfo) reviewTarget {
+	return reviewTarget{PRID: pr.PRID, AuthorID: pr.AuthorID, RequiredTags: pr.RequiredTags}
+}
+
+// selectPreferred выбирает ревьюверов стратегией по уровням предпочтения (см. preferCandidate): сначала среди лучших кандидатов,
+// нагрузку стратегия учитывает внутри уровня. Если все кандидаты равны, это обычный выбор стратегией.
+// Стратегия tieredStrategy получает все уровни одним вызовом
+func selectPreferred(ctx context.Context, strategy ReviewerSelectionStrategy, teamName string, candidates []models.ReviewerCandidate, requiredTags []string, count int) ([]string, error) {
+	ranked := make([]models.ReviewerCandidate, len(candidates))
+	copy(ranked, candidates)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return preferCandidate(ranked[i], ranked[j], requiredTags)
+	})
+
+	var tiers [][]models.ReviewerCandidate
+	for start := 0; start < len(ranked); {
+		end := start + 1
+		for end < len(ranked) && !preferCandidate(ranked[start], ranked[end], requiredTags) {
+			end++
+		}
+		tiers = append(tiers, ranked[start:end])
+		start = end
+	}
+
+	if tiered, ok := strategy.(tieredStrategy); ok {
+		return tiered.SelectTiered(ctx, teamName, tiers, count)
+	}
+
+	reviewers := []string{}
+	for _, tier := range tiers {
+		if len(reviewers) >= count {
+			break
+		}
+		picked, err := strategy.Select(ctx, teamName, tier, count-len(reviewers))
+		if err != nil {
+			return nil, err
+		}
+		reviewers = append(reviewers, picked...)
+	}
+	return reviewers, nil
+}
+
+// preferCandidate сообщает, выбирается ли a раньше b независимо от стратегии: больше совпавших требуемых тегов,
+// затем меньше недавних ревью PR того же автора
+func preferCandidate(a, b models.ReviewerCandidate, requiredTags []string) bool {
+	if overlapA, overlapB := tagOverlap(a.Tags, requiredTags), tagOverlap(b.Tags, requiredTags); overlapA != overlapB {
+		return overlapA > overlapB
+	}
+	return a.RecentReviews < b.RecentReviews
+}
+
+// candidatePool - кандидаты, из которых стратегия выбирает ревьюверов, и участники, исключённые до выбора, по причинам
+type candidatePool struct {
+	Strategy   string
+	TeamName   string
+	Candidates []models.ReviewerCandidate
+	Exclusions map[string][]string
+}
+
+// availableMembers отделяет от участников неактивных и отсутствующих сегодня, возвращая их по причинам исключения
+func availableMembers(members []models.UserAvailability) ([]models.User, map[string][]string) {
+	available := make([]models.User, 0, len(members))
+	exclusions := map[string][]string{}
+	for _, member := range members {
+		switch {
+		case !member.IsActive:
+			exclusions[models.ExclusionInactive] = append(exclusions[models.ExclusionInactive], member.UserID)
+		case member.OnLeave:
+			exclusions[models.ExclusionOnLeave] = append(exclusions[models.ExclusionOnLeave], member.UserID)
+		default:
+			available = append(available, member.User)
+		}
+	}
+	return available, exclusions
+}
+
+// newCandidatePool исключает из кандидатов автора, уже назначенных из excludeUserIDs и достигших предела открытых ревью.
+// exclusions - участники, не попавшие в candidates раньше, они сохраняются в пуле вместе с новыми исключениями
+func newCandidatePool(strategy, teamName string, candidates []models.ReviewerCandidate, authorID string, excludeUserIDs []string, exclusions map[string][]string) *candidatePool {
+	pool := &candidatePool{
+		Strategy:   strategy,
+		TeamName:   teamName,
+		Candidates: make([]models.ReviewerCandidate, 0, len(candidates)),
+		Exclusions: make(map[string][]string, len(exclusions)),
+	}
+	for reason, userIDs := range exclusions {
+		pool.Exclusions[reason] = append([]string{}, userIDs...)
+	}
+
+	for _, candidate := range candidates {
+		var reason string
+		switch {
+		case candidate.UserID == authorID:
+			reason = models.ExclusionAuthor
+		case contains(excludeUserIDs, candidate.UserID):
+			reason = models.ExclusionAlreadyAssigned
+		case candidate.MaxOpenReviews > 0 && candidate.OpenReviews >= candidate.MaxOpenReviews:
+			reason = models.ExclusionAtCapacity
+		default:
+			pool.Candidates = append(pool.Candidates, candidate)
+			continue
+		}
+		pool.Exclusions[reason] = append(pool.Exclusions[reason], candidate.UserID)
+	}
+	return pool
+}
+
+// explain объясняет выбор reviewerID из пула: нагрузка и совпадения берутся на момент выбора
+func (p *candidatePool) explain(reviewerID string, requiredTags []string) *models.AssignmentExplanation {
+	explanation := &models.AssignmentExplanation{
+		Strategy:      p.Strategy,
+		TeamName:      p.TeamName,
+		CandidatePool: len(p.Candidates),
+		Exclusions:    p.Exclusions,
+	}
+	for _, candidate := range p.Candidates {
+		if candidate.UserID == reviewerID {
+			explanation.OpenReviews = candidate.OpenReviews
+			explanation.MaxOpenReviews = candidate.MaxOpenReviews
+			explanation.TagOverlap = tagOverlap(candidate.Tags, requiredTags)
+			explanation.RecentReviews = candidate.RecentReviews
+			break
+		}
+	}
+	return explanation
+}
+
+// reviewerSelection - выбранные ревьюверы по порядку и запасные команды тех из них, кто назначен не из своей команды.
+// CodeOwners и UncoveredPatterns заполняются при назначении владельцев по изменённым файлам,
+// Explanations - для ревьюверов, выбранных в этот раз
+type reviewerSelection struct {
+	Reviewers         []string
+	FallbackTeams     map[string]string
+	CodeOwners        map[string]string
+	UncoveredPatterns []string
+	Explanations      map[string]*models.AssignmentExplanation
+}
+
+func (sel *reviewerSelection) add(reviewerID, fallbackTeam string) {
+	sel.Reviewers = append(sel.Reviewers, reviewerID)
+	if fallbackTeam == "" {
+		return
+	}
+	if sel.FallbackTeams == nil {
+		sel.FallbackTeams = map[string]string{}
+	}
+	sel.FallbackTeams[reviewerID] = fallbackTeam
+}
+
+func (sel *reviewerSelection) explain(reviewerID string, explanation *models.AssignmentExplanation) {
+	if explanation == nil {
+		return
+	}
+	if sel.Explanations == nil {
+		sel.Explanations = map[string]*models.AssignmentExplanation{}
+	}
+	sel.Explanations[reviewerID] = explanation
+}
+
+func (sel *reviewerSelection) merge(other *reviewerSelection) {
+	for _, reviewerID := range other.Reviewers {
+		sel.add(reviewerID, other.FallbackTeams[reviewerID])
+		sel.explain(reviewerID, other.Explanations[reviewerID])
+	}
+}
@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"math/rand"
 	"pr_task/internal/dto"
 	"time"
 
@@ -12,11 +11,36 @@ import (
 )
 
 type ServiceImpl struct {
-	repo repository.Repository
+	repo       repository.Repository
+	strategies map[string]ReviewerSelectionStrategy
 }
 
 func NewService(repo repository.Repository) Service {
-	return &ServiceImpl{repo: repo}
+	return &ServiceImpl{
+		repo:       repo,
+		strategies: NewReviewerSelectionStrategies(repo),
+	}
+}
+
+// withTx выполняет fn с копией сервиса, все обращения которой к репозиторию идут в одной транзакции
+func (s *ServiceImpl) withTx(ctx context.Context, fn func(tx *ServiceImpl) error) error {
+	return s.repo.WithTx(ctx, func(repo repository.Repository) error {
+		return fn(&ServiceImpl{
+			repo:       repo,
+			strategies: NewReviewerSelectionStrategies(repo),
+		})
+	})
+}
+
+// withPreviewTx выполняет fn с копией сервиса в транзакции только для чтения. Стратегии не сохраняют курсор ротации,
+// поэтому fn вычисляет назначения так же, как настоящая операция, ничего не записывая и не блокируя
+func (s *ServiceImpl) withPreviewTx(ctx context.Context, fn func(tx *ServiceImpl) error) error {
+	return s.repo.WithReadOnlyTx(ctx, func(repo repository.Repository) error {
+		return fn(&ServiceImpl{
+			repo:       repo,
+			strategies: newPreviewStrategies(repo),
+		})
+	})
 }
 
 func (s *ServiceImpl) CreateTeam(ctx context.Context, team models.Team) (*models.Team, error) {
@@ -27,6 +51,18 @@ func (s *ServiceImpl) CreateTeam(ctx context.Context, team models.Team) (*models
 	if exists {
 		return nil, errors.ErrTeamExists
 	}
+	if err := normalizeMemberTags(team.Members); err != nil {
+		return nil, err
+	}
+
+	// Участников других команд переводят явно через /users/move или PATCH /team, чтобы не потерять их открытые ревью
+	for _, member := range team.Members {
+		if _, err := s.repo.GetUser(ctx, member.UserID); err == nil {
+			return nil, errors.ErrUserInOtherTeam
+		} else if err.Error() != "user not found" {
+			return nil, err
+		}
+	}
 
 	if err := s.repo.CreateTeam(ctx, team); err != nil {
 		return nil, err
@@ -84,12 +120,19 @@ func (s *ServiceImpl) GetUserReviewPRs(ctx context.Context, userID string) (*dto
 
 	var shortPRs []dto.PullRequestShort
 	for _, pr := range prs {
-		shortPRs = append(shortPRs, dto.PullRequestShort{
+		short := dto.PullRequestShort{
 			PullRequestID:   pr.PullRequestID,
 			PullRequestName: pr.PullRequestName,
 			AuthorID:        pr.AuthorID,
 			Status:          pr.Status,
-		})
+		}
+		for _, review := range pr.Reviews {
+			if review.UserID == userID {
+				short.ReviewState = review.State
+				short.ReviewedAt = review.ReviewedAt
+			}
+		}
+		shortPRs = append(shortPRs, short)
 	}
 
 	return &dto.UserReviewResponse{
@@ -98,16 +141,79 @@ func (s *ServiceImpl) GetUserReviewPRs(ctx context.Context, userID string) (*dto
 	}, nil
 }
 
-func (s *ServiceImpl) CreatePullRequest(ctx context.Context, prID, name, authorID string) (*dto.PullRequest, error) {
-	exists, err := s.repo.PRExists(ctx, prID)
+// CreatePullRequest создаёт PR в одной транзакции с выбором ревьюверов, поэтому курсор ротации
+// сдвигается только при успешном создании
+func (s *ServiceImpl) CreatePullRequest(ctx context.Context, req dto.CreatePullRequestRequest) (*dto.PullRequest, error) {
+	prID, name, authorID := req.PullRequestID, req.PullRequestName, req.AuthorID
+
+	requiredTags, err := normalizeTags(req.RequiredTags)
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, errors.ErrPRExists
+
+	now := time.Now()
+	pr := dto.PullRequest{
+		PullRequestID:   prID,
+		PullRequestName: name,
+		AuthorID:        authorID,
+		Status:          "OPEN",
+		CreatedAt:       &now,
+		ChangedFiles:    req.ChangedFiles,
+		RequiredTags:    requiredTags,
 	}
 
-	author, err := s.repo.GetUser(ctx, authorID)
+	err = s.withTx(ctx, func(tx *ServiceImpl) error {
+		exists, err := tx.repo.PRExists(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.ErrPRExists
+		}
+
+		selection := &reviewerSelection{Reviewers: []string{}}
+		if req.IsDraft {
+			// Черновику ревьюверы не назначаются, проверяем только автора
+			if _, err := tx.repo.GetUser(ctx, authorID); err != nil {
+				if err.Error() == "user not found" {
+					return errors.ErrNotFound
+				}
+				return err
+			}
+			pr.Status = "DRAFT"
+		} else {
+			selection, err = tx.assignReviewers(ctx, &pr, req.ReviewerCount)
+			if err != nil {
+				return err
+			}
+		}
+
+		pr.AssignedReviewers = selection.Reviewers
+		pr.FallbackReviewers = selection.FallbackTeams
+		pr.CodeOwnerReviewers = selection.CodeOwners
+		pr.UncoveredPatterns = selection.UncoveredPatterns
+		pr.AssignmentExplanations = selection.Explanations
+
+		if err := tx.repo.CreatePR(ctx, pr, AssignedByCreate); err != nil {
+			// Параллельный запрос успел создать PR с тем же идентификатором
+			if err.Error() == "PR already exists" {
+				return errors.ErrPRExists
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// assignReviewers выбирает ревьюверов для PR: сначала владельцев затронутых изменёнными файлами областей,
+// затем оставшиеся места заполняет по настройкам команды автора. Везде предпочитаются кандидаты с требуемыми тегами
+func (s *ServiceImpl) assignReviewers(ctx context.Context, pr *dto.PullRequest, reviewerCount *int) (*reviewerSelection, error) {
+	author, err := s.repo.GetUser(ctx, pr.AuthorID)
 	if err != nil {
 		if err.Error() == "user not found" {
 			return nil, errors.ErrNotFound
@@ -115,52 +221,157 @@ func (s *ServiceImpl) CreatePullRequest(ctx context.Context, prID, name, authorI
 		return nil, err
 	}
 
-	reviewers, err := s.selectReviewers(ctx, author.TeamName, authorID, 2)
+	settings, err := s.GetTeamSettings(ctx, author.TeamName)
 	if err != nil {
 		return nil, err
 	}
 
-	now := time.Now()
-	pr := dto.PullRequest{
-		PullRequestID:     prID,
-		PullRequestName:   name,
-		AuthorID:          authorID,
-		Status:            "OPEN",
-		AssignedReviewers: reviewers,
-		CreatedAt:         &now,
+	count := settings.MaxReviewers
+	if reviewerCount != nil {
+		if *reviewerCount < settings.MinReviewers || *reviewerCount > settings.MaxReviewers {
+			return nil, errors.ErrInvalidReviewerCount
+		}
+		count = *reviewerCount
 	}
 
-	if err := s.repo.CreatePR(ctx, pr); err != nil {
+	target := prTarget(pr)
+	selection, err := s.selectCodeOwners(ctx, target, pr.ChangedFiles)
+	if err != nil {
 		return nil, err
 	}
 
-	return &pr, nil
+	// Владельцев может оказаться больше count, тогда команда автора ревьюверов не добавляет
+	if remaining := count - len(selection.Reviewers); remaining > 0 {
+		teamSelection, err := s.selectReviewers(ctx, settings, target, append([]string{pr.AuthorID}, selection.Reviewers...), remaining)
+		if err != nil {
+			return nil, err
+		}
+		selection.merge(teamSelection)
+	}
+	if len(selection.Reviewers) < settings.MinReviewers {
+		return nil, errors.ErrNoCandidate
+	}
+	return selection, nil
 }
 
-func (s *ServiceImpl) MergePullRequest(ctx context.Context, prID string) (*dto.PullRequest, error) {
-	pr, err := s.repo.GetPR(ctx, prID)
-	if err != nil {
-		if err.Error() == "PR not found" {
-			return nil, errors.ErrNotFound
+// MarkPullRequestReady переводит черновик в OPEN и назначает ревьюверов по текущим настройкам команды автора
+func (s *ServiceImpl) MarkPullRequestReady(ctx context.Context, req dto.MarkReadyRequest) (*dto.PullRequest, error) {
+	var ready *dto.PullRequest
+	err := s.withTx(ctx, func(tx *ServiceImpl) error {
+		pr, err := tx.repo.GetPR(ctx, req.PullRequestID)
+		if err != nil {
+			if err.Error() == "PR not found" {
+				return errors.ErrNotFound
+			}
+			return err
+		}
+
+		switch pr.Status {
+		case "OPEN":
+			ready = pr
+			return nil
+		case "MERGED":
+			return errors.ErrPRMerged
+		case "CLOSED":
+			return errors.ErrPRClosed
 		}
+
+		selection, err := tx.assignReviewers(ctx, pr, req.ReviewerCount)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.updatePRReviewers(ctx, pr.PullRequestID, selection, AssignedByMarkReady); err != nil {
+			return err
+		}
+		if err := tx.repo.UpdatePRStatus(ctx, pr.PullRequestID, "OPEN", nil, nil); err != nil {
+			return err
+		}
+
+		pr.Status = "OPEN"
+		pr.AssignedReviewers = selection.Reviewers
+		pr.FallbackReviewers = selection.FallbackTeams
+		pr.CodeOwnerReviewers = selection.CodeOwners
+		pr.UncoveredPatterns = selection.UncoveredPatterns
+		pr.AssignmentExplanations = selection.Explanations
+		ready = pr
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	if pr.Status == "MERGED" {
-		return pr, nil
-	}
+	return ready, nil
+}
 
-	now := time.Now()
-	if err := s.repo.UpdatePRStatus(ctx, prID, "MERGED", &now); err != nil {
+func (s *ServiceImpl) MergePullRequest(ctx context.Context, req dto.MergePullRequestRequest) (*dto.PullRequest, error) {
+	prID := req.PullRequestID
+
+	var merged *dto.PullRequest
+	err := s.withTx(ctx, func(tx *ServiceImpl) error {
+		pr, err := tx.repo.GetPR(ctx, prID)
+		if err != nil {
+			if err.Error() == "PR not found" {
+				return errors.ErrNotFound
+			}
+			return err
+		}
+
+		if pr.Status == "MERGED" {
+			merged = pr
+			return nil
+		}
+		if pr.Status == "CLOSED" {
+			return errors.ErrPRClosed
+		}
+		if pr.Status == "DRAFT" {
+			return errors.ErrPRDraft
+		}
+
+		unmet, err := tx.unmetMergeConditions(ctx, pr)
+		if err != nil {
+			return err
+		}
+		if len(unmet) > 0 && !req.Force {
+			return &errors.MergeBlockedError{Conditions: unmet}
+		}
+
+		now := time.Now()
+		if err := tx.repo.UpdatePRStatus(ctx, prID, "MERGED", &now, nil); err != nil {
+			return err
+		}
+		if err := tx.repo.RecordMerge(ctx, prID, req.MergedBy, req.Force, unmet); err != nil {
+			return err
+		}
+
+		pr.Status = "MERGED"
+		pr.MergedAt = &now
+		pr.MergedBy = req.MergedBy
+		pr.ForceMerged = req.Force
+		pr.BypassedConditions = unmet
+		merged = pr
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	pr.Status = "MERGED"
-	pr.MergedAt = &now
+	return merged, nil
+}
+
+// GetPullRequest возвращает PR с состоянием ревью и объяснениями выбора автоматически назначенных ревьюверов
+func (s *ServiceImpl) GetPullRequest(ctx context.Context, prID string) (*dto.PullRequest, error) {
+	pr, err := s.repo.GetPR(ctx, prID)
+	if err != nil {
+		if err.Error() == "PR not found" {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
 	return pr, nil
 }
 
-func (s *ServiceImpl) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*dto.ReassignResponse, error) {
+func (s *ServiceImpl) ClosePullRequest(ctx context.Context, prID string) (*dto.PullRequest, error) {
 	pr, err := s.repo.GetPR(ctx, prID)
 	if err != nil {
 		if err.Error() == "PR not found" {
@@ -169,69 +380,315 @@ func (s *ServiceImpl) ReassignReviewer(ctx context.Context, prID, oldUserID stri
 		return nil, err
 	}
 
+	if pr.Status == "CLOSED" {
+		return pr, nil
+	}
 	if pr.Status == "MERGED" {
 		return nil, errors.ErrPRMerged
 	}
 
-	if !contains(pr.AssignedReviewers, oldUserID) {
-		return nil, errors.ErrNotAssigned
+	now := time.Now()
+	if err := s.repo.UpdatePRStatus(ctx, prID, "CLOSED", nil, &now); err != nil {
+		return nil, err
 	}
 
-	oldReviewer, err := s.repo.GetUser(ctx, oldUserID)
+	pr.Status = "CLOSED"
+	pr.ClosedAt = &now
+	return pr, nil
+}
+
+// ReopenPullRequest возвращает закрытый PR в OPEN. Пока PR был закрыт, массовая деактивация его не трогала,
+// поэтому ставшие неактивными ревьюверы заменяются по стратегии команды, а при отсутствии замены снимаются
+func (s *ServiceImpl) ReopenPullRequest(ctx context.Context, prID string) (*dto.PullRequest, error) {
+	var reopened *dto.PullRequest
+	err := s.withTx(ctx, func(tx *ServiceImpl) error {
+		pr, err := tx.repo.GetPR(ctx, prID)
+		if err != nil {
+			if err.Error() == "PR not found" {
+				return errors.ErrNotFound
+			}
+			return err
+		}
+
+		if pr.Status == "OPEN" {
+			reopened = pr
+			return nil
+		}
+		if pr.Status == "MERGED" {
+			return errors.ErrPRMerged
+		}
+		if pr.Status == "DRAFT" {
+			return errors.ErrPRDraft
+		}
+
+		selection, err := tx.replaceInactiveReviewers(ctx, pr)
+		if err != nil {
+			return err
+		}
+		if !sameReviewers(selection.Reviewers, pr.AssignedReviewers) {
+			if err := tx.updatePRReviewers(ctx, prID, selection, AssignedByReopen); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.repo.UpdatePRStatus(ctx, prID, "OPEN", nil, nil); err != nil {
+			return err
+		}
+
+		pr.Status = "OPEN"
+		pr.ClosedAt = nil
+		pr.AssignedReviewers = selection.Reviewers
+		pr.FallbackReviewers = selection.FallbackTeams
+		pr.AssignmentExplanations = selection.Explanations
+		reopened = pr
+		return nil
+	})
 	if err != nil {
-		if err.Error() == "user not found" {
-			return nil, errors.ErrNotFound
+		return nil, err
+	}
+
+	return reopened, nil
+}
+
+// replaceInactiveReviewers заменяет неактивных ревьюверов PR по стратегии их команды.
+// FallbackTeams и Explanations результата описывают всех ревьюверов, включая оставшихся
+func (s *ServiceImpl) replaceInactiveReviewers(ctx context.Context, pr *dto.PullRequest) (*reviewerSelection, error) {
+	selection := &reviewerSelection{Reviewers: make([]string, 0, len(pr.AssignedReviewers))}
+	for _, reviewerID := range pr.AssignedReviewers {
+		reviewer, err := s.repo.GetUser(ctx, reviewerID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				continue
+			}
+			return nil, err
+		}
+		if reviewer.IsActive {
+			selection.add(reviewerID, pr.FallbackReviewers[reviewerID])
+			selection.explain(reviewerID, pr.AssignmentExplanations[reviewerID])
+			continue
+		}
+
+		settings, err := s.GetTeamSettings(ctx, reviewer.TeamName)
+		if err != nil {
+			return nil, err
+		}
+
+		excludeIDs := append(append([]string{pr.AuthorID}, pr.AssignedReviewers...), selection.Reviewers...)
+		replacement, err := s.selectReviewers(ctx, settings, prTarget(pr), excludeIDs, 1)
+		if err != nil {
+			return nil, err
+		}
+		selection.merge(replacement)
+	}
+	return selection, nil
+}
+
+func (s *ServiceImpl) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*dto.ReassignResponse, error) {
+	var response *dto.ReassignResponse
+	err := s.withTx(ctx, func(tx *ServiceImpl) error {
+		pr, err := tx.repo.GetPR(ctx, prID)
+		if err != nil {
+			if err.Error() == "PR not found" {
+				return errors.ErrNotFound
+			}
+			return err
+		}
+
+		if pr.Status == "MERGED" {
+			return errors.ErrPRMerged
+		}
+		if pr.Status == "CLOSED" {
+			return errors.ErrPRClosed
+		}
+		if pr.Status == "DRAFT" {
+			return errors.ErrPRDraft
+		}
+
+		if !contains(pr.AssignedReviewers, oldUserID) {
+			return errors.ErrNotAssigned
+		}
+
+		oldReviewer, err := tx.repo.GetUser(ctx, oldUserID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				return errors.ErrNotFound
+			}
+			return err
+		}
+
+		settings, err := tx.GetTeamSettings(ctx, oldReviewer.TeamName)
+		if err != nil {
+			return err
+		}
+
+		excludeIDs := append(pr.AssignedReviewers, pr.AuthorID)
+		candidates, err := tx.selectReviewers(ctx, settings, prTarget(pr), excludeIDs, 1)
+		if err != nil {
+			return err
+		}
+		if len(candidates.Reviewers) == 0 {
+			return errors.ErrNoCandidate
 		}
+		newReviewerID := candidates.Reviewers[0]
+		fallbackTeam := candidates.FallbackTeams[newReviewerID]
+		explanation := candidates.Explanations[newReviewerID]
+
+		selection := &reviewerSelection{Reviewers: []string{}}
+		for _, reviewerID := range replaceElement(pr.AssignedReviewers, oldUserID, newReviewerID) {
+			if reviewerID == newReviewerID {
+				selection.add(reviewerID, fallbackTeam)
+				selection.explain(reviewerID, explanation)
+				continue
+			}
+			selection.add(reviewerID, pr.FallbackReviewers[reviewerID])
+		}
+		if err := tx.updatePRReviewers(ctx, prID, selection, AssignedByReassign); err != nil {
+			return err
+		}
+
+		pr.AssignedReviewers = selection.Reviewers
+		pr.FallbackReviewers = selection.FallbackTeams
+		if pr.AssignmentExplanations == nil {
+			pr.AssignmentExplanations = map[string]*models.AssignmentExplanation{}
+		}
+		delete(pr.AssignmentExplanations, oldUserID)
+		pr.AssignmentExplanations[newReviewerID] = explanation
+		response = &dto.ReassignResponse{
+			PR:           pr,
+			ReplacedBy:   newReviewerID,
+			FallbackTeam: fallbackTeam,
+			Explanation:  explanation,
+		}
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	excludeIDs := append(pr.AssignedReviewers, pr.AuthorID)
-	newReviewer, err := s.repo.GetRandomActiveTeamMember(ctx, oldReviewer.TeamName, excludeIDs)
+	return response, nil
+}
+
+// selectReviewers выбирает до maxReviewers ревьюверов из команды settings по её стратегии.
+// Недостающих добирает из запасных команд по порядку, каждую - по её собственным настройкам.
+// В каждой команде сначала выбираются кандидаты с большим числом требуемых тегов target, затем реже ревьюившие его автора
+func (s *ServiceImpl) selectReviewers(ctx context.Context, settings *models.TeamSettings, target reviewTarget, excludeUserIDs []string, maxReviewers int) (*reviewerSelection, error) {
+	selection, err := s.selectTeamReviewers(ctx, settings, target, excludeUserIDs, maxReviewers)
 	if err != nil {
-		if err.Error() == "no active team members available" {
-			return nil, errors.ErrNoCandidate
+		return nil, err
+	}
+
+	for _, teamName := range settings.FallbackTeams {
+		if len(selection.Reviewers) >= maxReviewers {
+			break
 		}
+
+		fallbackSettings, err := s.GetTeamSettings(ctx, teamName)
+		if err != nil {
+			return nil, err
+		}
+
+		excludeIDs := append(append([]string{}, excludeUserIDs...), selection.Reviewers...)
+		fallback, err := s.selectTeamReviewers(ctx, fallbackSettings, target, excludeIDs, maxReviewers-len(selection.Reviewers))
+		if err != nil {
+			return nil, err
+		}
+		for _, reviewerID := range fallback.Reviewers {
+			selection.add(reviewerID, teamName)
+			selection.explain(reviewerID, fallback.Explanations[reviewerID])
+		}
+	}
+	return selection, nil
+}
+
+// selectTeamReviewers выбирает до maxReviewers ревьюверов из команды settings и объясняет выбор каждого
+func (s *ServiceImpl) selectTeamReviewers(ctx context.Context, settings *models.TeamSettings, target reviewTarget, excludeUserIDs []string, maxReviewers int) (*reviewerSelection, error) {
+	selection := &reviewerSelection{Reviewers: []string{}}
+	members, err := s.repo.GetUserAvailability(ctx, nil, []string{settings.TeamName})
+	if err != nil {
 		return nil, err
 	}
 
-	newReviewers := replaceElement(pr.AssignedReviewers, oldUserID, newReviewer.UserID)
-	if err := s.repo.UpdatePRReviewers(ctx, prID, newReviewers); err != nil {
+	available, exclusions := availableMembers(members)
+	if len(available) == 0 {
+		return selection, nil
+	}
+
+	candidates, err := s.reviewerCandidates(ctx, settings, available)
+	if err != nil {
 		return nil, err
 	}
 
-	pr.AssignedReviewers = newReviewers
-	return &dto.ReassignResponse{
-		PR:         pr,
-		ReplacedBy: newReviewer.UserID,
-	}, nil
-}
+	if err := s.applyRecentReviews(ctx, settings, target, candidates); err != nil {
+		return nil, err
+	}
 
-func (s *ServiceImpl) selectReviewers(ctx context.Context, teamName, excludeUserID string, maxReviewers int) ([]string, error) {
-	activeMembers, err := s.repo.GetActiveTeamMembers(ctx, teamName, excludeUserID)
+	strategy := s.teamStrategy(settings)
+	pool := newCandidatePool(strategy.Name(), settings.TeamName, candidates, target.AuthorID, excludeUserIDs, exclusions)
+	reviewers, err := selectPreferred(ctx, strategy, settings.TeamName, pool.Candidates, target.RequiredTags, maxReviewers)
 	if err != nil {
 		return nil, err
 	}
+	for _, reviewerID := range reviewers {
+		selection.add(reviewerID, "")
+		selection.explain(reviewerID, pool.explain(reviewerID, target.RequiredTags))
+	}
+	return selection, nil
+}
 
-	if len(activeMembers) == 0 {
-		return []string{}, nil
+// updatePRReviewers сохраняет состав ревьюверов PR, отмечает назначенных из запасных команд и объяснения выбора новых
+func (s *ServiceImpl) updatePRReviewers(ctx context.Context, prID string, selection *reviewerSelection, assignedBy string) error {
+	if err := s.repo.UpdatePRReviewers(ctx, prID, selection.Reviewers, assignedBy); err != nil {
+		return err
+	}
+	if err := s.repo.SetReviewerFallbackTeams(ctx, prID, selection.FallbackTeams); err != nil {
+		return err
 	}
+	return s.repo.SetReviewerExplanations(ctx, prID, selection.Explanations)
+}
 
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+// reviewerCandidates собирает кандидатов из участников команды с их текущей нагрузкой и пределом открытых ревью
+func (s *ServiceImpl) reviewerCandidates(ctx context.Context, settings *models.TeamSettings, members []models.User) ([]models.ReviewerCandidate, error) {
+	userIDs := make([]string, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
 
-	shuffled := make([]models.User, len(activeMembers))
-	copy(shuffled, activeMembers)
+	openReviews, err := s.repo.GetOpenReviewCounts(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
 
-	rng.Shuffle(len(shuffled), func(i, j int) {
-		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
-	})
+	candidates := make([]models.ReviewerCandidate, len(members))
+	for i, member := range members {
+		candidates[i] = models.ReviewerCandidate{
+			UserID:         member.UserID,
+			OpenReviews:    openReviews[member.UserID],
+			MaxOpenReviews: reviewCapacity(settings, member),
+			Tags:           member.Tags,
+		}
+	}
+	return candidates, nil
+}
 
-	count := min(len(shuffled), maxReviewers)
-	reviewers := make([]string, count)
-	for i := 0; i < count; i++ {
-		reviewers[i] = shuffled[i].UserID
+// applyRecentReviews отмечает у кандидатов, в скольких из последних settings.PairingLookback PR автора target они были ревьюверами
+func (s *ServiceImpl) applyRecentReviews(ctx context.Context, settings *models.TeamSettings, target reviewTarget, candidates []models.ReviewerCandidate) error {
+	if settings.PairingLookback == 0 || target.AuthorID == "" {
+		return nil
 	}
 
-	return reviewers, nil
+	history, err := s.repo.GetAuthorReviewHistory(ctx, target.AuthorID, target.PRID, settings.PairingLookback)
+	if err != nil {
+		return err
+	}
+
+	recent := make(map[string]int)
+	for _, assignment := range history {
+		recent[assignment.ReviewerID]++
+	}
+	for i := range candidates {
+		candidates[i].RecentReviews = recent[candidates[i].UserID]
+	}
+	return nil
 }
 
 func contains(slice []string, item string) bool {
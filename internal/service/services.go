@@ -9,15 +9,44 @@ import (
 type Service interface {
 	CreateTeam(ctx context.Context, team models.Team) (*models.Team, error)
 	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	ListTeams(ctx context.Context, limit, offset int) (*dto.TeamListResponse, error)
+	UpdateTeamMembers(ctx context.Context, req dto.UpdateTeamMembersRequest) (*dto.UpdateTeamMembersResponse, error)
+	RenameTeam(ctx context.Context, req dto.RenameTeamRequest) (*models.Team, error)
+	DeleteTeam(ctx context.Context, req dto.DeleteTeamRequest) (*dto.DeleteTeamResponse, error)
+	GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+	UpdateTeamSettings(ctx context.Context, req dto.TeamSettingsRequest) (*models.TeamSettings, error)
 
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	ListUsers(ctx context.Context, filter models.UserFilter) (*dto.UserListResponse, error)
+	UpdateUser(ctx context.Context, req dto.UpdateUserRequest) (*models.User, error)
+	DeleteUser(ctx context.Context, req dto.DeleteUserRequest) (*dto.DeleteUserResponse, error)
 	SetUserActive(ctx context.Context, userID string, isActive bool) (*models.User, error)
+	SetUserMaxOpenReviews(ctx context.Context, req dto.SetMaxOpenReviewsRequest) (*models.User, error)
+	MoveUser(ctx context.Context, req dto.MoveUserRequest) (*dto.MoveUserResponse, error)
+	CreateUserLeave(ctx context.Context, req dto.CreateUserLeaveRequest) (*dto.UserLeaveResponse, error)
+	GetUserLeaves(ctx context.Context, userID string) (*dto.UserLeavesResponse, error)
+	DeleteUserLeave(ctx context.Context, leaveID string) (*models.UserLeave, error)
+	SetUserTags(ctx context.Context, req dto.SetUserTagsRequest) (*models.User, error)
+	ListTags(ctx context.Context) (*dto.TagListResponse, error)
 	GetUserReviewPRs(ctx context.Context, userID string) (*dto.UserReviewResponse, error)
-	MassDeactivateTeamUsers(ctx context.Context, teamName string, excludeUserIDs []string) (*dto.MassDeactivationResponse, error)
+	MassDeactivateTeamUsers(ctx context.Context, req dto.MassDeactivationRequest) (*dto.MassDeactivationResponse, error)
+	MassActivateUsers(ctx context.Context, req dto.MassActivationRequest) (*dto.MassActivationResponse, error)
+	EnqueueMassDeactivation(ctx context.Context, req dto.MassDeactivationRequest) (*models.Job, error)
+	GetJob(ctx context.Context, jobID string) (*models.Job, error)
 
-	CreatePullRequest(ctx context.Context, prID, name, authorID string) (*dto.PullRequest, error)
-	MergePullRequest(ctx context.Context, prID string) (*dto.PullRequest, error)
+	CreatePullRequest(ctx context.Context, req dto.CreatePullRequestRequest) (*dto.PullRequest, error)
+	GetPullRequest(ctx context.Context, prID string) (*dto.PullRequest, error)
+	MergePullRequest(ctx context.Context, req dto.MergePullRequestRequest) (*dto.PullRequest, error)
+	ClosePullRequest(ctx context.Context, prID string) (*dto.PullRequest, error)
+	ReopenPullRequest(ctx context.Context, prID string) (*dto.PullRequest, error)
+	MarkPullRequestReady(ctx context.Context, req dto.MarkReadyRequest) (*dto.PullRequest, error)
+	SubmitReview(ctx context.Context, req dto.SubmitReviewRequest) (*dto.PullRequest, error)
 	ReassignReviewer(ctx context.Context, prID, oldUserID string) (*dto.ReassignResponse, error)
 
+	GetCodeOwners(ctx context.Context) (*dto.CodeOwnersResponse, error)
+	ReplaceCodeOwners(ctx context.Context, req dto.CodeOwnersRequest) (*dto.CodeOwnersResponse, error)
+	ImportCodeOwners(ctx context.Context, content string) (*dto.CodeOwnersResponse, error)
+
 	GetUserReviewStats(ctx context.Context) ([]dto.UserReviewStatsResponse, error)
 	GetPRReviewStats(ctx context.Context) ([]dto.PRReviewStatsResponse, error)
 	GetOverallStats(ctx context.Context) (*dto.OverallStatsResponse, error)
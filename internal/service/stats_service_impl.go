@@ -14,10 +14,13 @@ func (s *ServiceImpl) GetUserReviewStats(ctx context.Context) ([]dto.UserReviewS
 	var response []dto.UserReviewStatsResponse
 	for _, stat := range stats {
 		response = append(response, dto.UserReviewStatsResponse{
-			UserID:      stat.UserID,
-			Username:    stat.Username,
-			TeamName:    stat.TeamName,
-			ReviewCount: stat.ReviewCount,
+			UserID:         stat.UserID,
+			Username:       stat.Username,
+			TeamName:       stat.TeamName,
+			ReviewCount:    stat.ReviewCount,
+			OpenReviews:    stat.OpenReviews,
+			MaxOpenReviews: stat.MaxOpenReviews,
+			AtCapacity:     stat.MaxOpenReviews != nil && stat.OpenReviews >= *stat.MaxOpenReviews,
 		})
 	}
 
@@ -54,6 +57,8 @@ func (s *ServiceImpl) GetOverallStats(ctx context.Context) (*dto.OverallStatsRes
 		TotalPRs:        stats.TotalPRs,
 		OpenPRs:         stats.OpenPRs,
 		MergedPRs:       stats.MergedPRs,
+		ClosedPRs:       stats.ClosedPRs,
+		DraftPRs:        stats.DraftPRs,
 		TotalUsers:      stats.TotalUsers,
 		ActiveUsers:     stats.ActiveUsers,
 		TotalReviews:    stats.TotalReviews,
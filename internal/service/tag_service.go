@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._+#-]{0,31}$`)
+
+// SetUserTags заменяет теги навыков пользователя
+func (s *ServiceImpl) SetUserTags(ctx context.Context, req dto.SetUserTagsRequest) (*models.User, error) {
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateUserTags(ctx, req.UserID, tags); err != nil {
+		if err.Error() == "user not found" {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return s.GetUser(ctx, req.UserID)
+}
+
+func (s *ServiceImpl) ListTags(ctx context.Context) (*dto.TagListResponse, error) {
+	tags, err := s.repo.GetTagUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.TagListResponse{Tags: tags}, nil
+}
+
+// normalizeTags приводит теги к нижнему регистру, убирает повторы и сортирует. nil остаётся nil
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !tagPattern.MatchString(tag) {
+			return nil, errors.ErrInvalidTags
+		}
+		if !contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// normalizeMemberTags нормализует теги участников команды из запроса
+func normalizeMemberTags(members []models.TeamMember) error {
+	for i := range members {
+		tags, err := normalizeTags(members[i].Tags)
+		if err != nil {
+			return err
+		}
+		members[i].Tags = tags
+	}
+	return nil
+}
+
+// tagOverlap - число требуемых тегов, которые есть у кандидата
+func tagOverlap(tags, requiredTags []string) int {
+	overlap := 0
+	for _, tag := range requiredTags {
+		if contains(tags, tag) {
+			overlap++
+		}
+	}
+	return overlap
+}
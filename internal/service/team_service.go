@@ -0,0 +1,333 @@
+package services
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+	"time"
+)
+
+// Политики обработки открытых PR пользователей, которых удаляют из команды или вместе с командой
+const (
+	OpenPRPolicyReject   = "reject"
+	OpenPRPolicyReassign = "reassign"
+	OpenPRPolicyClose    = "close"
+)
+
+func (s *ServiceImpl) ListTeams(ctx context.Context, limit, offset int) (*dto.TeamListResponse, error) {
+	teams, total, err := s.repo.ListTeams(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TeamListResponse{
+		Teams:  teams,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}, nil
+}
+
+// UpdateTeamMembers удаляет и добавляет участников команды в одной транзакции.
+// Открытые PR удаляемых участников обрабатываются по req.OpenPRPolicy до их удаления,
+// открытые ревью участников, переводимых из других команд, - по req.ReviewPolicy
+func (s *ServiceImpl) UpdateTeamMembers(ctx context.Context, req dto.UpdateTeamMembersRequest) (*dto.UpdateTeamMembersResponse, error) {
+	policy, err := openPRPolicy(req.OpenPRPolicy)
+	if err != nil {
+		return nil, err
+	}
+	movePolicy, err := reviewPolicy(req.ReviewPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if err := normalizeMemberTags(req.Add); err != nil {
+		return nil, err
+	}
+
+	response := &dto.UpdateTeamMembersResponse{}
+	err = s.withTx(ctx, func(tx *ServiceImpl) error {
+		team, err := tx.GetTeam(ctx, req.TeamName)
+		if err != nil {
+			return err
+		}
+
+		memberIDs := teamMemberIDs(team)
+		for _, userID := range req.Remove {
+			if !contains(memberIDs, userID) {
+				return errors.ErrNotFound
+			}
+		}
+
+		if len(req.Remove) > 0 {
+			handoff, err := tx.handOffOpenPRs(ctx, req.Remove, policy, req.NewAuthorID)
+			if err != nil {
+				return err
+			}
+			if err := tx.repo.DeleteUsers(ctx, req.Remove); err != nil {
+				return err
+			}
+			response.OpenPRHandoff = *handoff
+			response.RemovedUserIDs = req.Remove
+		}
+
+		for _, member := range req.Add {
+			user, err := tx.repo.GetUser(ctx, member.UserID)
+			if err != nil && err.Error() != "user not found" {
+				return err
+			}
+			if user != nil && user.TeamName != req.TeamName {
+				handoff, err := tx.moveUser(ctx, user, req.TeamName, movePolicy)
+				if err != nil {
+					return err
+				}
+				response.MovedUserIDs = append(response.MovedUserIDs, user.UserID)
+				response.Reassignments = append(response.Reassignments, handoff.Reassignments...)
+				response.NotReassignedPRs = append(response.NotReassignedPRs, handoff.NotReassignedPRs...)
+			}
+
+			if err := tx.repo.CreateOrUpdateUser(ctx, member, req.TeamName); err != nil {
+				return err
+			}
+		}
+
+		response.Team, err = tx.GetTeam(ctx, req.TeamName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *ServiceImpl) RenameTeam(ctx context.Context, req dto.RenameTeamRequest) (*models.Team, error) {
+	var renamed *models.Team
+	err := s.withTx(ctx, func(tx *ServiceImpl) error {
+		exists, err := tx.repo.TeamExists(ctx, req.NewTeamName)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.ErrTeamExists
+		}
+
+		if err := tx.repo.RenameTeam(ctx, req.TeamName, req.NewTeamName); err != nil {
+			if err.Error() == "team not found" {
+				return errors.ErrNotFound
+			}
+			return err
+		}
+
+		renamed, err = tx.GetTeam(ctx, req.NewTeamName)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return renamed, nil
+}
+
+// DeleteTeam удаляет команду и её участников. Открытые PR участников обрабатываются по req.OpenPRPolicy
+func (s *ServiceImpl) DeleteTeam(ctx context.Context, req dto.DeleteTeamRequest) (*dto.DeleteTeamResponse, error) {
+	policy, err := openPRPolicy(req.OpenPRPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.DeleteTeamResponse{TeamName: req.TeamName}
+	err = s.withTx(ctx, func(tx *ServiceImpl) error {
+		team, err := tx.GetTeam(ctx, req.TeamName)
+		if err != nil {
+			return err
+		}
+
+		userIDs := teamMemberIDs(team)
+		if len(userIDs) > 0 {
+			handoff, err := tx.handOffOpenPRs(ctx, userIDs, policy, req.NewAuthorID)
+			if err != nil {
+				return err
+			}
+			response.OpenPRHandoff = *handoff
+			response.DeletedUserIDs = userIDs
+		}
+
+		if err := tx.repo.DeleteTeam(ctx, req.TeamName); err != nil {
+			if err.Error() == "team not found" {
+				return errors.ErrNotFound
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// handOffOpenPRs освобождает открытые PR от пользователей userIDs перед их удалением.
+// С политикой reject любой открытый PR, который они создали или ревьюят, запрещает удаление.
+// С reassign созданные ими PR и черновики передаются newAuthorID, с close - закрываются.
+// Их места ревьюверов занимают активные участники команды автора PR. Должен вызываться внутри транзакции
+func (s *ServiceImpl) handOffOpenPRs(ctx context.Context, userIDs []string, policy, newAuthorID string) (*models.OpenPRHandoff, error) {
+	authored, err := s.repo.GetOpenPRsByAuthors(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	reviewed, err := s.repo.GetOpenPRsByReviewers(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	handoff := &models.OpenPRHandoff{}
+	if len(authored) == 0 && len(reviewed) == 0 {
+		return handoff, nil
+	}
+
+	if policy == OpenPRPolicyReject {
+		var prIDs []string
+		for _, pr := range append(authored, reviewed...) {
+			if !contains(prIDs, pr.PRID) {
+				prIDs = append(prIDs, pr.PRID)
+			}
+		}
+		return nil, &errors.OpenPRsError{PRIDs: prIDs}
+	}
+
+	if policy == OpenPRPolicyReassign && len(authored) > 0 {
+		if newAuthorID == "" || contains(userIDs, newAuthorID) {
+			return nil, errors.ErrInvalidPolicy
+		}
+		if _, err := s.repo.GetUser(ctx, newAuthorID); err != nil {
+			if err.Error() == "user not found" {
+				return nil, errors.ErrNotFound
+			}
+			return nil, err
+		}
+	}
+
+	openPRs := reviewed
+	for _, pr := range authored {
+		if policy == OpenPRPolicyClose {
+			now := time.Now()
+			if err := s.repo.UpdatePRStatus(ctx, pr.PRID, "CLOSED", nil, &now); err != nil {
+				return nil, err
+			}
+			handoff.ClosedPRs = append(handoff.ClosedPRs, pr.PRID)
+			continue
+		}
+
+		if err := s.repo.UpdatePRAuthor(ctx, pr.PRID, newAuthorID); err != nil {
+			return nil, err
+		}
+		handoff.TransferredPRs = append(handoff.TransferredPRs, pr.PRID)
+		// Новый автор мог быть ревьювером переданного PR, тогда его тоже нужно заменить
+		if contains(pr.AssignedReviewers, newAuthorID) && !containsPR(reviewed, pr.PRID) {
+			openPRs = append(openPRs, pr)
+		}
+	}
+
+	var updates []models.PRReviewersUpdate
+	for _, pr := range openPRs {
+		if contains(handoff.ClosedPRs, pr.PRID) {
+			continue
+		}
+		if contains(handoff.TransferredPRs, pr.PRID) {
+			pr.AuthorID = newAuthorID
+		}
+
+		removed := append(append([]string{}, userIDs...), pr.AuthorID)
+		selection, err := s.replaceRemovedReviewers(ctx, pr, removed)
+		if err != nil {
+			return nil, err
+		}
+		if sameReviewers(selection.Reviewers, pr.AssignedReviewers) {
+			continue
+		}
+
+		updates = append(updates, models.PRReviewersUpdate{
+			PRID:          pr.PRID,
+			Reviewers:     selection.Reviewers,
+			FallbackTeams: selection.FallbackTeams,
+			Explanations:  selection.Explanations,
+		})
+		handoff.PRChanges = append(handoff.PRChanges, models.PRReviewersChange{
+			PRID:          pr.PRID,
+			Before:        pr.AssignedReviewers,
+			After:         selection.Reviewers,
+			FallbackTeams: selection.FallbackTeams,
+		})
+	}
+
+	if err := s.repo.UpdatePRReviewersBatch(ctx, updates, AssignedByUserRemoval); err != nil {
+		return nil, err
+	}
+	return handoff, nil
+}
+
+// replaceRemovedReviewers заменяет ревьюверов PR из removed активными участниками команды автора по её стратегии
+// или её запасных команд. Если замены нет, ревьювер просто снимается
+func (s *ServiceImpl) replaceRemovedReviewers(ctx context.Context, pr models.OpenPRInfo, removed []string) (*reviewerSelection, error) {
+	var settings *models.TeamSettings
+	selection := &reviewerSelection{Reviewers: make([]string, 0, len(pr.AssignedReviewers))}
+	for _, reviewerID := range pr.AssignedReviewers {
+		if !contains(removed, reviewerID) {
+			selection.add(reviewerID, pr.FallbackReviewers[reviewerID])
+			continue
+		}
+
+		if settings == nil {
+			author, err := s.repo.GetUser(ctx, pr.AuthorID)
+			if err != nil {
+				if err.Error() == "user not found" {
+					continue
+				}
+				return nil, err
+			}
+			settings, err = s.GetTeamSettings(ctx, author.TeamName)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		excludeIDs := append(append(append([]string{}, removed...), pr.AssignedReviewers...), selection.Reviewers...)
+		replacement, err := s.selectReviewers(ctx, settings, openPRTarget(pr), excludeIDs, 1)
+		if err != nil {
+			return nil, err
+		}
+		selection.merge(replacement)
+	}
+	return selection, nil
+}
+
+// openPRPolicy проверяет политику обработки открытых PR, пустая политика означает reject
+func openPRPolicy(policy string) (string, error) {
+	switch policy {
+	case "":
+		return OpenPRPolicyReject, nil
+	case OpenPRPolicyReject, OpenPRPolicyReassign, OpenPRPolicyClose:
+		return policy, nil
+	default:
+		return "", errors.ErrInvalidPolicy
+	}
+}
+
+func teamMemberIDs(team *models.Team) []string {
+	userIDs := make([]string, len(team.Members))
+	for i, member := range team.Members {
+		userIDs[i] = member.UserID
+	}
+	return userIDs
+}
+
+func containsPR(prs []models.OpenPRInfo, prID string) bool {
+	for _, pr := range prs {
+		if pr.PRID == prID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+)
+
+// maxPairingLookback ограничивает число последних PR автора, по которым ищутся повторные пары автор-ревьювер
+const maxPairingLookback = 100
+
+func (s *ServiceImpl) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	settings, err := s.repo.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		if err.Error() == "team not found" {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (s *ServiceImpl) UpdateTeamSettings(ctx context.Context, req dto.TeamSettingsRequest) (*models.TeamSettings, error) {
+	settings, err := s.GetTeamSettings(ctx, req.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ReviewerStrategy != nil {
+		if _, ok := s.strategies[*req.ReviewerStrategy]; !ok {
+			return nil, errors.ErrInvalidStrategy
+		}
+		settings.ReviewerStrategy = *req.ReviewerStrategy
+	}
+	if req.MinReviewers != nil {
+		settings.MinReviewers = *req.MinReviewers
+	}
+	if req.MaxReviewers != nil {
+		settings.MaxReviewers = *req.MaxReviewers
+	}
+	if req.RequiredApprovals != nil {
+		settings.RequiredApprovals = *req.RequiredApprovals
+	}
+	if req.BlockOnChangesRequested != nil {
+		settings.BlockOnChangesRequested = *req.BlockOnChangesRequested
+	}
+	if req.RequireLeadApproval != nil {
+		settings.RequireLeadApproval = *req.RequireLeadApproval
+	}
+	if req.DefaultMaxOpenReviews != nil {
+		if *req.DefaultMaxOpenReviews < 0 {
+			return nil, errors.ErrInvalidSettings
+		}
+		settings.DefaultMaxOpenReviews = req.DefaultMaxOpenReviews
+		if *req.DefaultMaxOpenReviews == 0 {
+			settings.DefaultMaxOpenReviews = nil
+		}
+	}
+
+	if req.PairingLookback != nil {
+		if *req.PairingLookback < 0 || *req.PairingLookback > maxPairingLookback {
+			return nil, errors.ErrInvalidSettings
+		}
+		settings.PairingLookback = *req.PairingLookback
+	}
+
+	if req.FallbackTeams != nil {
+		if err := s.validateFallbackTeams(ctx, req.TeamName, req.FallbackTeams); err != nil {
+			return nil, err
+		}
+		settings.FallbackTeams = req.FallbackTeams
+	}
+
+	if settings.MinReviewers < 0 || settings.MaxReviewers < 1 || settings.MinReviewers > settings.MaxReviewers {
+		return nil, errors.ErrInvalidSettings
+	}
+	// Больше одобрений, чем ревьюверов, получить нельзя
+	if settings.RequiredApprovals < 0 || settings.RequiredApprovals > settings.MaxReviewers {
+		return nil, errors.ErrInvalidSettings
+	}
+
+	if err := s.repo.UpdateTeamSettings(ctx, *settings); err != nil {
+		if err.Error() == "team not found" {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return settings, nil
+}
+
+// validateFallbackTeams проверяет, что запасные команды существуют, не повторяются и не совпадают с самой командой
+func (s *ServiceImpl) validateFallbackTeams(ctx context.Context, teamName string, fallbackTeams []string) error {
+	seen := make([]string, 0, len(fallbackTeams))
+	for _, fallbackTeam := range fallbackTeams {
+		if fallbackTeam == teamName || contains(seen, fallbackTeam) {
+			return errors.ErrInvalidFallbackTeams
+		}
+		seen = append(seen, fallbackTeam)
+
+		exists, err := s.repo.TeamExists(ctx, fallbackTeam)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errors.ErrInvalidFallbackTeams
+		}
+	}
+	return nil
+}
+
+// reviewCapacity возвращает предел открытых ревью участника: личный или команды по умолчанию, 0 - без ограничения
+func reviewCapacity(settings *models.TeamSettings, user models.User) int {
+	if user.MaxOpenReviews != nil {
+		return *user.MaxOpenReviews
+	}
+	if settings.DefaultMaxOpenReviews != nil {
+		return *settings.DefaultMaxOpenReviews
+	}
+	return 0
+}
+
+func (s *ServiceImpl) teamStrategy(settings *models.TeamSettings) ReviewerSelectionStrategy {
+	strategy, ok := s.strategies[settings.ReviewerStrategy]
+	if !ok {
+		return s.strategies[DefaultStrategy]
+	}
+	return strategy
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+)
+
+// Политики обработки открытых ревью пользователя, которого переводят в другую команду
+const (
+	ReviewPolicyReassign = "reassign"
+	ReviewPolicyKeep     = "keep"
+)
+
+func (s *ServiceImpl) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.repo.GetUser(ctx, userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// ListUsers возвращает страницу пользователей и курсор следующей страницы
+func (s *ServiceImpl) ListUsers(ctx context.Context, filter models.UserFilter) (*dto.UserListResponse, error) {
+	limit := filter.Limit
+	// Лишняя запись показывает, есть ли следующая страница
+	filter.Limit++
+
+	users, err := s.repo.ListUsers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.UserListResponse{Users: users}
+	if len(users) > limit {
+		response.Users = users[:limit]
+		response.NextCursor = users[limit-1].UserID
+	}
+	return response, nil
+}
+
+func (s *ServiceImpl) UpdateUser(ctx context.Context, req dto.UpdateUserRequest) (*models.User, error) {
+	if err := s.repo.UpdateUsername(ctx, req.UserID, req.Username); err != nil {
+		if err.Error() == "user not found" {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return s.GetUser(ctx, req.UserID)
+}
+
+// SetUserMaxOpenReviews задаёт личный предел открытых ревью, 0 возвращает предел команды по умолчанию.
+// Уже назначенные ревью сверх предела не снимаются
+func (s *ServiceImpl) SetUserMaxOpenReviews(ctx context.Context, req dto.SetMaxOpenReviewsRequest) (*models.User, error) {
+	if req.MaxOpenReviews < 0 {
+		return nil, errors.ErrInvalidSettings
+	}
+
+	var maxOpenReviews *int
+	if req.MaxOpenReviews > 0 {
+		maxOpenReviews = &req.MaxOpenReviews
+	}
+
+	if err := s.repo.UpdateUserMaxOpenReviews(ctx, req.UserID, maxOpenReviews); err != nil {
+		if err.Error() == "user not found" {
+			return nil, errors.ErrNotFound
+		}
+		return nil, err
+	}
+	return s.GetUser(ctx, req.UserID)
+}
+
+// DeleteUser удаляет пользователя. Созданные им открытые PR и черновики запрещают удаление,
+// если req.OpenPRPolicy не reassign или close. Его открытые ревью передаются участникам команды автора PR
+func (s *ServiceImpl) DeleteUser(ctx context.Context, req dto.DeleteUserRequest) (*dto.DeleteUserResponse, error) {
+	policy, err := openPRPolicy(req.OpenPRPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.DeleteUserResponse{UserID: req.UserID}
+	err = s.withTx(ctx, func(tx *ServiceImpl) error {
+		if _, err := tx.GetUser(ctx, req.UserID); err != nil {
+			return err
+		}
+
+		userIDs := []string{req.UserID}
+		if policy == OpenPRPolicyReject {
+			authored, err := tx.repo.GetOpenPRsByAuthors(ctx, userIDs)
+			if err != nil {
+				return err
+			}
+			if len(authored) > 0 {
+				prIDs := make([]string, len(authored))
+				for i, pr := range authored {
+					prIDs[i] = pr.PRID
+				}
+				return &errors.OpenPRsError{PRIDs: prIDs}
+			}
+			// Созданных PR нет, а ревью удаляемого пользователя передаются при любой политике
+			policy = OpenPRPolicyReassign
+		}
+
+		handoff, err := tx.handOffOpenPRs(ctx, userIDs, policy, req.NewAuthorID)
+		if err != nil {
+			return err
+		}
+		response.OpenPRHandoff = *handoff
+
+		return tx.repo.DeleteUsers(ctx, userIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// MoveUser переводит пользователя в другую команду и по req.ReviewPolicy передаёт его открытые ревью участникам прежней команды
+func (s *ServiceImpl) MoveUser(ctx context.Context, req dto.MoveUserRequest) (*dto.MoveUserResponse, error) {
+	policy, err := reviewPolicy(req.ReviewPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.MoveUserResponse{}
+	err = s.withTx(ctx, func(tx *ServiceImpl) error {
+		user, err := tx.repo.GetUser(ctx, req.UserID)
+		if err != nil {
+			if err.Error() == "user not found" {
+				return errors.ErrNotFound
+			}
+			return err
+		}
+
+		if _, err := tx.GetTeamSettings(ctx, req.TeamName); err != nil {
+			return err
+		}
+
+		handoff, err := tx.moveUser(ctx, user, req.TeamName, policy)
+		if err != nil {
+			return err
+		}
+
+		response.FromTeam = user.TeamName
+		response.ReviewHandoff = *handoff
+		user.TeamName = req.TeamName
+		response.User = user
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// moveUser переводит пользователя в команду teamName. С политикой reassign каждое его открытое ревью
+// передаётся участнику прежней команды по её стратегии; если замены нет, пользователь остаётся ревьювером.
+// Должен вызываться внутри транзакции
+func (s *ServiceImpl) moveUser(ctx context.Context, user *models.User, teamName, policy string) (*models.ReviewHandoff, error) {
+	handoff := &models.ReviewHandoff{}
+	if user.TeamName == teamName {
+		return handoff, nil
+	}
+
+	if err := s.repo.UpdateUserTeam(ctx, user.UserID, teamName); err != nil {
+		return nil, err
+	}
+	if policy == ReviewPolicyKeep {
+		return handoff, nil
+	}
+
+	settings, err := s.GetTeamSettings(ctx, user.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reassignOpenReviews(ctx, user.UserID, settings, AssignedByUserMove)
+}
+
+// reassignOpenReviews передаёт каждое открытое ревью пользователя участнику команды settings по её стратегии
+// или участнику её запасных команд.
+// Если замены нет, пользователь остаётся ревьювером. Должен вызываться внутри транзакции
+func (s *ServiceImpl) reassignOpenReviews(ctx context.Context, userID string, settings *models.TeamSettings, assignedBy string) (*models.ReviewHandoff, error) {
+	openPRs, err := s.repo.GetOpenPRsByReviewers(ctx, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+
+	handoff := &models.ReviewHandoff{}
+	for _, pr := range openPRs {
+		excludeIDs := append([]string{pr.AuthorID}, pr.AssignedReviewers...)
+		candidates, err := s.selectReviewers(ctx, settings, openPRTarget(pr), excludeIDs, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(candidates.Reviewers) == 0 {
+			handoff.NotReassignedPRs = append(handoff.NotReassignedPRs, pr.PRID)
+			continue
+		}
+		newReviewerID := candidates.Reviewers[0]
+
+		// Ревьюверы обновляются по одному PR, чтобы нагрузка следующих кандидатов учитывала уже сделанные замены
+		selection := &reviewerSelection{
+			Reviewers:     replaceElement(pr.AssignedReviewers, userID, newReviewerID),
+			FallbackTeams: candidates.FallbackTeams,
+			Explanations:  candidates.Explanations,
+		}
+		if err := s.updatePRReviewers(ctx, pr.PRID, selection, assignedBy); err != nil {
+			return nil, err
+		}
+
+		handoff.Reassignments = append(handoff.Reassignments, models.ReviewerReassignment{
+			PRID:          pr.PRID,
+			OldReviewerID: userID,
+			NewReviewerID: newReviewerID,
+			FallbackTeam:  candidates.FallbackTeams[newReviewerID],
+		})
+	}
+	return handoff, nil
+}
+
+// reviewPolicy проверяет политику обработки открытых ревью, пустая политика означает reassign
+func reviewPolicy(policy string) (string, error) {
+	switch policy {
+	case "":
+		return ReviewPolicyReassign, nil
+	case ReviewPolicyReassign, ReviewPolicyKeep:
+		return policy, nil
+	default:
+		return "", errors.ErrInvalidReviewPolicy
+	}
+}
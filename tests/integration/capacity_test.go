@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewCapacityIntegration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("CreatePR_SkipsMembersAtCapacity", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.SetUserMaxOpenReviews(ctx, dto.SetMaxOpenReviewsRequest{UserID: "u2", MaxOpenReviews: 1})
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-600", PullRequestName: "First", AuthorID: "u1"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+
+		// У u2 уже одно открытое ревью - его предел
+		pr, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-601", PullRequestName: "Second", AuthorID: "u1"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u3"}, pr.AssignedReviewers)
+	})
+
+	t.Run("TeamDefault_OverriddenByUser", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		defaultMax := 1
+		settings, err := testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", DefaultMaxOpenReviews: &defaultMax})
+		require.NoError(t, err)
+		require.NotNil(t, settings.DefaultMaxOpenReviews)
+
+		user, err := testService.SetUserMaxOpenReviews(ctx, dto.SetMaxOpenReviewsRequest{UserID: "u3", MaxOpenReviews: 5})
+		require.NoError(t, err)
+		require.NotNil(t, user.MaxOpenReviews)
+		assert.Equal(t, 5, *user.MaxOpenReviews)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-602", PullRequestName: "First", AuthorID: "u1"})
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-603", PullRequestName: "Second", AuthorID: "u1"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u3"}, pr.AssignedReviewers)
+
+		stats, err := testService.GetUserReviewStats(ctx)
+		require.NoError(t, err)
+		for _, stat := range stats {
+			switch stat.UserID {
+			case "u2":
+				assert.Equal(t, 1, stat.OpenReviews)
+				require.NotNil(t, stat.MaxOpenReviews)
+				assert.Equal(t, 1, *stat.MaxOpenReviews)
+				assert.True(t, stat.AtCapacity)
+			case "u3":
+				assert.Equal(t, 2, stat.OpenReviews)
+				require.NotNil(t, stat.MaxOpenReviews)
+				assert.Equal(t, 5, *stat.MaxOpenReviews)
+				assert.False(t, stat.AtCapacity)
+			case "u5":
+				assert.Nil(t, stat.MaxOpenReviews)
+			}
+		}
+
+		// 0 снимает ограничение команды
+		noLimit := 0
+		settings, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", DefaultMaxOpenReviews: &noLimit})
+		require.NoError(t, err)
+		assert.Nil(t, settings.DefaultMaxOpenReviews)
+	})
+
+	t.Run("Reassign_NoCandidateOnlyWhenAllFull", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		reviewerCount := 1
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-604", PullRequestName: "Reassign", AuthorID: "u1", ReviewerCount: &reviewerCount})
+		require.NoError(t, err)
+		reviewer := pr.AssignedReviewers[0]
+		other := "u2"
+		if reviewer == "u2" {
+			other = "u3"
+		}
+
+		// Другой участник с пределом 1 и свободным местом всё ещё подходит
+		_, err = testService.SetUserMaxOpenReviews(ctx, dto.SetMaxOpenReviewsRequest{UserID: other, MaxOpenReviews: 1})
+		require.NoError(t, err)
+
+		result, err := testService.ReassignReviewer(ctx, "pr-604", reviewer)
+		require.NoError(t, err)
+		assert.Equal(t, other, result.ReplacedBy)
+
+		// other достиг предела, reviewer получает предел 1 и занимается новым PR - заменить other некем
+		_, err = testService.SetUserMaxOpenReviews(ctx, dto.SetMaxOpenReviewsRequest{UserID: reviewer, MaxOpenReviews: 1})
+		require.NoError(t, err)
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-605", PullRequestName: "Fill", AuthorID: "u1", ReviewerCount: &reviewerCount})
+		require.NoError(t, err)
+
+		_, err = testService.ReassignReviewer(ctx, "pr-604", other)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrNoCandidate))
+	})
+
+	t.Run("SetMaxOpenReviews_Validation", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.SetUserMaxOpenReviews(ctx, dto.SetMaxOpenReviewsRequest{UserID: "u2", MaxOpenReviews: -1})
+		assert.True(t, errors.Is(err, errors.ErrInvalidSettings))
+
+		_, err = testService.SetUserMaxOpenReviews(ctx, dto.SetMaxOpenReviewsRequest{UserID: "nonexistent", MaxOpenReviews: 2})
+		assert.True(t, errors.Is(err, errors.ErrNotFound))
+	})
+}
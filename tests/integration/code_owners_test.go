@@ -0,0 +1,170 @@
+package integration
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodeOwnersIntegration(t *testing.T) {
+	ctx := context.Background()
+
+	setOwners := func(t *testing.T, rules ...models.CodeOwnerRule) {
+		_, err := testService.ReplaceCodeOwners(ctx, dto.CodeOwnersRequest{Rules: rules})
+		require.NoError(t, err)
+	}
+
+	t.Run("CreatePR_AssignsOwnerFromAnotherTeam", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		setOwners(t, models.CodeOwnerRule{Pattern: "/deploy/", Teams: []string{"devops"}})
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{
+			PullRequestID:   "pr-800",
+			PullRequestName: "Deploy",
+			AuthorID:        "u1",
+			ChangedFiles:    []string{"deploy/k8s/app.yaml", "internal/service/service_impl.go"},
+		})
+		require.NoError(t, err)
+		require.Len(t, pr.AssignedReviewers, 2)
+		owner := pr.AssignedReviewers[0]
+		assert.Contains(t, []string{"u7", "u8"}, owner)
+		assert.Contains(t, []string{"u2", "u3"}, pr.AssignedReviewers[1])
+		assert.Equal(t, map[string]string{owner: "/deploy/"}, pr.CodeOwnerReviewers)
+		assert.Empty(t, pr.UncoveredPatterns)
+
+		stored, err := testRepo.GetPR(ctx, "pr-800")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"deploy/k8s/app.yaml", "internal/service/service_impl.go"}, stored.ChangedFiles)
+	})
+
+	t.Run("CreatePR_LastMatchingRuleWins", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		setOwners(t,
+			models.CodeOwnerRule{Pattern: "*.go", Users: []string{"u2"}},
+			models.CodeOwnerRule{Pattern: "/internal/billing/", Users: []string{"u6"}},
+		)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{
+			PullRequestID:   "pr-801",
+			PullRequestName: "Billing",
+			AuthorID:        "u1",
+			ChangedFiles:    []string{"internal/billing/pay.go"},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, pr.AssignedReviewers)
+		assert.Equal(t, "u6", pr.AssignedReviewers[0])
+		assert.Equal(t, map[string]string{"u6": "/internal/billing/"}, pr.CodeOwnerReviewers)
+
+		// Каждая затронутая область получает владельца, даже сверх reviewer_count
+		reviewerCount := 1
+		pr, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{
+			PullRequestID:   "pr-802",
+			PullRequestName: "Billing and CLI",
+			AuthorID:        "u1",
+			ReviewerCount:   &reviewerCount,
+			ChangedFiles:    []string{"internal/billing/pay.go", "cmd/main.go"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u2", "u6"}, pr.AssignedReviewers)
+		assert.Equal(t, map[string]string{"u2": "*.go", "u6": "/internal/billing/"}, pr.CodeOwnerReviewers)
+	})
+
+	t.Run("CreatePR_ReportsUncoveredPatterns", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		// u4 неактивен, а автор не может быть владельцем-ревьювером своего PR
+		setOwners(t,
+			models.CodeOwnerRule{Pattern: "docs/", Users: []string{"u4"}},
+			models.CodeOwnerRule{Pattern: "*.sql", Users: []string{"u1"}},
+		)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{
+			PullRequestID:   "pr-803",
+			PullRequestName: "Docs",
+			AuthorID:        "u1",
+			ChangedFiles:    []string{"docs/readme.md", "migrations/0001.sql"},
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+		assert.Empty(t, pr.CodeOwnerReviewers)
+		assert.Equal(t, []string{"docs/", "*.sql"}, pr.UncoveredPatterns)
+	})
+
+	t.Run("MarkReady_RoutesByChangedFiles", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		setOwners(t, models.CodeOwnerRule{Pattern: "/web/**", Users: []string{"u5"}})
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{
+			PullRequestID:   "pr-804",
+			PullRequestName: "Web",
+			AuthorID:        "u1",
+			IsDraft:         true,
+			ChangedFiles:    []string{"web/src/app.tsx"},
+		})
+		require.NoError(t, err)
+
+		pr, err := testService.MarkPullRequestReady(ctx, dto.MarkReadyRequest{PullRequestID: "pr-804"})
+		require.NoError(t, err)
+		require.NotEmpty(t, pr.AssignedReviewers)
+		assert.Equal(t, "u5", pr.AssignedReviewers[0])
+		assert.Equal(t, map[string]string{"u5": "/web/**"}, pr.CodeOwnerReviewers)
+	})
+
+	t.Run("Import_ParsesCodeOwnersFile", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		content := `# Владельцы по умолчанию
+*            @u1 @acme/backend
+
+/deploy/     @acme/devops  # инфраструктура
+*.tsx        @u5 @u6
+/deploy/docs/
+`
+		result, err := testService.ImportCodeOwners(ctx, content)
+		require.NoError(t, err)
+		expected := []models.CodeOwnerRule{
+			{Pattern: "*", Users: []string{"u1"}, Teams: []string{"backend"}},
+			{Pattern: "/deploy/", Users: []string{}, Teams: []string{"devops"}},
+			{Pattern: "*.tsx", Users: []string{"u5", "u6"}, Teams: []string{}},
+			{Pattern: "/deploy/docs/", Users: []string{}, Teams: []string{}},
+		}
+		assert.Equal(t, expected, result.Rules)
+
+		stored, err := testService.GetCodeOwners(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, expected, stored.Rules)
+
+		for _, invalid := range []string{
+			"*.md docs@example.com",
+			"[Docs]\n*.md @u1",
+			"*.md @ghost",
+			"*.md @acme/ghosts",
+		} {
+			_, err := testService.ImportCodeOwners(ctx, invalid)
+			assert.True(t, errors.Is(err, errors.ErrInvalidCodeOwners), "content %q", invalid)
+		}
+
+		// Ошибочный импорт не меняет реестр
+		stored, err = testService.GetCodeOwners(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, expected, stored.Rules)
+	})
+}
@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+	services "pr_task/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignmentExplanationIntegration(t *testing.T) {
+	ctx := context.Background()
+
+	createPR := func(t *testing.T, prID string) *dto.PullRequest {
+		reviewerCount := 1
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{
+			PullRequestID:   prID,
+			PullRequestName: "Explain " + prID,
+			AuthorID:        "u1",
+			ReviewerCount:   &reviewerCount,
+		})
+		require.NoError(t, err)
+		require.Len(t, pr.AssignedReviewers, 1)
+		return pr
+	}
+
+	other := func(reviewerID string) string {
+		if reviewerID == "u2" {
+			return "u3"
+		}
+		return "u2"
+	}
+
+	t.Run("CreatePR_ExplainsExclusions", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		today := time.Now().Format("2006-01-02")
+		_, err = testService.CreateUserLeave(ctx, dto.CreateUserLeaveRequest{UserID: "u3", StartsOn: today, EndsOn: today})
+		require.NoError(t, err)
+
+		pr := createPR(t, "pr-1100")
+		assert.Equal(t, []string{"u2"}, pr.AssignedReviewers)
+		expected := &models.AssignmentExplanation{
+			Strategy:      services.DefaultStrategy,
+			TeamName:      "backend",
+			CandidatePool: 1,
+			Exclusions: map[string][]string{
+				models.ExclusionAuthor:   {"u1"},
+				models.ExclusionInactive: {"u4"},
+				models.ExclusionOnLeave:  {"u3"},
+			},
+		}
+		assert.Equal(t, map[string]*models.AssignmentExplanation{"u2": expected}, pr.AssignmentExplanations)
+
+		stored, err := testService.GetPullRequest(ctx, "pr-1100")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]*models.AssignmentExplanation{"u2": expected}, stored.AssignmentExplanations)
+		require.Len(t, stored.Reviews, 1)
+		assert.Equal(t, services.AssignedByCreate, stored.Reviews[0].AssignedBy)
+
+		_, err = testService.GetPullRequest(ctx, "pr-missing")
+		assert.True(t, errors.Is(err, errors.ErrNotFound))
+	})
+
+	t.Run("CreatePR_ExplainsCapacity", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		busy := createPR(t, "pr-1101").AssignedReviewers[0]
+		_, err = testService.SetUserMaxOpenReviews(ctx, dto.SetMaxOpenReviewsRequest{UserID: busy, MaxOpenReviews: 1})
+		require.NoError(t, err)
+
+		pr := createPR(t, "pr-1102")
+		free := other(busy)
+		assert.Equal(t, []string{free}, pr.AssignedReviewers)
+		explanation := pr.AssignmentExplanations[free]
+		require.NotNil(t, explanation)
+		assert.Equal(t, 1, explanation.CandidatePool)
+		assert.Equal(t, 0, explanation.OpenReviews)
+		assert.Equal(t, []string{busy}, explanation.Exclusions[models.ExclusionAtCapacity])
+	})
+
+	t.Run("Reassign_ExplainsReplacement", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		oldReviewer := createPR(t, "pr-1103").AssignedReviewers[0]
+		result, err := testService.ReassignReviewer(ctx, "pr-1103", oldReviewer)
+		require.NoError(t, err)
+		newReviewer := other(oldReviewer)
+		assert.Equal(t, newReviewer, result.ReplacedBy)
+		require.NotNil(t, result.Explanation)
+		assert.Equal(t, 1, result.Explanation.CandidatePool)
+		assert.Equal(t, []string{oldReviewer}, result.Explanation.Exclusions[models.ExclusionAlreadyAssigned])
+
+		stored, err := testService.GetPullRequest(ctx, "pr-1103")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]*models.AssignmentExplanation{newReviewer: result.Explanation}, stored.AssignmentExplanations)
+	})
+
+	t.Run("MarkReady_ExplainsAssignment", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-1105", PullRequestName: "Explain draft", AuthorID: "u1", IsDraft: true})
+		require.NoError(t, err)
+
+		reviewerCount := 1
+		ready, err := testService.MarkPullRequestReady(ctx, dto.MarkReadyRequest{PullRequestID: "pr-1105", ReviewerCount: &reviewerCount})
+		require.NoError(t, err)
+		require.Len(t, ready.AssignedReviewers, 1)
+		explanation := ready.AssignmentExplanations[ready.AssignedReviewers[0]]
+		require.NotNil(t, explanation)
+		assert.Equal(t, 2, explanation.CandidatePool)
+		assert.Equal(t, []string{"u4"}, explanation.Exclusions[models.ExclusionInactive])
+
+		stored, err := testService.GetPullRequest(ctx, "pr-1105")
+		require.NoError(t, err)
+		assert.Equal(t, stored.AssignmentExplanations, ready.AssignmentExplanations)
+	})
+
+	t.Run("Reopen_ExplainsReplacement", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		inactive := createPR(t, "pr-1106").AssignedReviewers[0]
+		_, err = testService.ClosePullRequest(ctx, "pr-1106")
+		require.NoError(t, err)
+		_, err = testService.SetUserActive(ctx, inactive, false)
+		require.NoError(t, err)
+
+		reopened, err := testService.ReopenPullRequest(ctx, "pr-1106")
+		require.NoError(t, err)
+		replacement := other(inactive)
+		assert.Equal(t, []string{replacement}, reopened.AssignedReviewers)
+		explanation := reopened.AssignmentExplanations[replacement]
+		require.NotNil(t, explanation)
+		assert.Equal(t, 1, explanation.CandidatePool)
+		assert.ElementsMatch(t, []string{inactive, "u4"}, explanation.Exclusions[models.ExclusionInactive])
+
+		stored, err := testService.GetPullRequest(ctx, "pr-1106")
+		require.NoError(t, err)
+		assert.Equal(t, stored.AssignmentExplanations, reopened.AssignmentExplanations)
+		require.Len(t, stored.Reviews, 1)
+		assert.Equal(t, services.AssignedByReopen, stored.Reviews[0].AssignedBy)
+	})
+
+	t.Run("MassDeactivation_ExplainsReplacement", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		deactivated := createPR(t, "pr-1104").AssignedReviewers[0]
+		replacement := other(deactivated)
+		result, err := testService.MassDeactivateTeamUsers(ctx, dto.MassDeactivationRequest{TeamName: "backend", ExcludeUserIDs: []string{"u1", replacement}})
+		require.NoError(t, err)
+		require.Len(t, result.PRChanges, 1)
+		assert.Equal(t, []string{replacement}, result.PRChanges[0].After)
+
+		explanation := result.PRChanges[0].Explanations[replacement]
+		require.NotNil(t, explanation)
+		assert.Equal(t, 1, explanation.CandidatePool)
+		assert.Equal(t, []string{"u1"}, explanation.Exclusions[models.ExclusionAuthor])
+		assert.ElementsMatch(t, []string{deactivated, "u4"}, explanation.Exclusions[models.ExclusionInactive])
+
+		stored, err := testService.GetPullRequest(ctx, "pr-1104")
+		require.NoError(t, err)
+		assert.Equal(t, explanation, stored.AssignmentExplanations[replacement])
+		require.Len(t, stored.Reviews, 1)
+		assert.Equal(t, services.AssignedByMassDeactivation, stored.Reviews[0].AssignedBy)
+	})
+}
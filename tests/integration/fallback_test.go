@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallbackTeamsIntegration(t *testing.T) {
+	ctx := context.Background()
+
+	setFallback := func(t *testing.T, teamName string, fallbackTeams ...string) {
+		if fallbackTeams == nil {
+			fallbackTeams = []string{}
+		}
+		_, err := testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: teamName, FallbackTeams: fallbackTeams})
+		require.NoError(t, err)
+	}
+
+	t.Run("CreatePR_FillsFromFallbackTeam", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		// Без запасных команд у автора из frontend только один ревьювер
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-700", PullRequestName: "Home", AuthorID: "u5"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u6"}, pr.AssignedReviewers)
+		assert.Empty(t, pr.FallbackReviewers)
+
+		setFallback(t, "frontend", "devops")
+
+		pr, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-701", PullRequestName: "Fallback", AuthorID: "u5"})
+		require.NoError(t, err)
+		require.Len(t, pr.AssignedReviewers, 2)
+		assert.Equal(t, "u6", pr.AssignedReviewers[0])
+		fallbackReviewer := pr.AssignedReviewers[1]
+		assert.Contains(t, []string{"u7", "u8"}, fallbackReviewer)
+		assert.Equal(t, map[string]string{fallbackReviewer: "devops"}, pr.FallbackReviewers)
+
+		stored, err := testRepo.GetPR(ctx, "pr-701")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{fallbackReviewer: "devops"}, stored.FallbackReviewers)
+		require.Len(t, stored.Reviews, 2)
+		assert.Empty(t, stored.Reviews[0].FallbackTeam)
+		assert.Equal(t, "devops", stored.Reviews[1].FallbackTeam)
+	})
+
+	t.Run("Reassign_UsesFallbackTeam", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		reviewerCount := 1
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-702", PullRequestName: "Reassign", AuthorID: "u5", ReviewerCount: &reviewerCount})
+		require.NoError(t, err)
+
+		_, err = testService.ReassignReviewer(ctx, "pr-702", "u6")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrNoCandidate))
+
+		setFallback(t, "frontend", "devops")
+
+		result, err := testService.ReassignReviewer(ctx, "pr-702", "u6")
+		require.NoError(t, err)
+		assert.Contains(t, []string{"u7", "u8"}, result.ReplacedBy)
+		assert.Equal(t, "devops", result.FallbackTeam)
+		assert.Equal(t, map[string]string{result.ReplacedBy: "devops"}, result.PR.FallbackReviewers)
+	})
+
+	t.Run("FallbackTeams_FollowedInOrder", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		maxReviewers := 3
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "devops", MaxReviewers: &maxReviewers, FallbackTeams: []string{"frontend", "backend"}})
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-703", PullRequestName: "Order", AuthorID: "u7"})
+		require.NoError(t, err)
+		require.Len(t, pr.AssignedReviewers, 3)
+		assert.Equal(t, "u8", pr.AssignedReviewers[0])
+		for _, reviewerID := range pr.AssignedReviewers[1:] {
+			assert.Equal(t, "frontend", pr.FallbackReviewers[reviewerID])
+		}
+	})
+
+	t.Run("FallbackTeams_Validation", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		for _, fallbackTeams := range [][]string{{"frontend"}, {"devops", "devops"}, {"nonexistent"}} {
+			_, err := testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "frontend", FallbackTeams: fallbackTeams})
+			assert.True(t, errors.Is(err, errors.ErrInvalidFallbackTeams), "fallback teams %v", fallbackTeams)
+		}
+
+		setFallback(t, "frontend", "devops", "backend")
+
+		_, err = testService.RenameTeam(ctx, dto.RenameTeamRequest{TeamName: "devops", NewTeamName: "ops"})
+		require.NoError(t, err)
+
+		settings, err := testService.GetTeamSettings(ctx, "frontend")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ops", "backend"}, settings.FallbackTeams)
+
+		// Пустой список удаляет запасные команды
+		setFallback(t, "frontend")
+		settings, err = testService.GetTeamSettings(ctx, "frontend")
+		require.NoError(t, err)
+		assert.Empty(t, settings.FallbackTeams)
+	})
+}
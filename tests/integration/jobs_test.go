@@ -0,0 +1,129 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	services "pr_task/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobsIntegration(t *testing.T) {
+	ctx := context.Background()
+	runner := services.NewJobRunner(testRepo, services.JobRunnerConfig{Lease: time.Minute, MaxAttempts: 2})
+
+	t.Run("MassDeactivationJob_Succeeds", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-400", PullRequestName: "Job", AuthorID: "u1"})
+		require.NoError(t, err)
+
+		job, err := testService.EnqueueMassDeactivation(ctx, dto.MassDeactivationRequest{TeamName: "backend", ExcludeUserIDs: []string{"u1"}})
+		require.NoError(t, err)
+		assert.Equal(t, services.JobStatusPending, job.Status)
+
+		// Пока задача в очереди, пользователи не деактивированы
+		user, err := testRepo.GetUser(ctx, "u2")
+		require.NoError(t, err)
+		assert.True(t, user.IsActive)
+
+		require.True(t, runner.RunNext(ctx))
+		assert.False(t, runner.RunNext(ctx))
+
+		job, err = testService.GetJob(ctx, job.JobID)
+		require.NoError(t, err)
+		assert.Equal(t, services.JobStatusSucceeded, job.Status)
+		assert.Equal(t, 1, job.Attempts)
+		assert.Equal(t, 1, job.Total)
+		assert.Equal(t, 1, job.Progress)
+		assert.NotNil(t, job.FinishedAt)
+
+		var result dto.MassDeactivationResponse
+		require.NoError(t, json.Unmarshal(job.Result, &result))
+		assert.Equal(t, 2, result.DeactivatedUsers)
+		assert.Equal(t, 1, result.UpdatedPRs)
+		assert.NotEmpty(t, result.OperationID)
+
+		user, err = testRepo.GetUser(ctx, "u2")
+		require.NoError(t, err)
+		assert.False(t, user.IsActive)
+	})
+
+	t.Run("MassDeactivationJob_TeamNotFound", func(t *testing.T) {
+		clearTestData()
+
+		_, err := testService.EnqueueMassDeactivation(ctx, dto.MassDeactivationRequest{TeamName: "nonexistent"})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrNotFound))
+	})
+
+	t.Run("Job_ResumedAfterLeaseExpired", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		job, err := testService.EnqueueMassDeactivation(ctx, dto.MassDeactivationRequest{TeamName: "frontend"})
+		require.NoError(t, err)
+
+		// Имитируем исполнителя, который взял задачу и упал
+		claimed, err := testRepo.ClaimJob(ctx, time.Minute, 2)
+		require.NoError(t, err)
+		require.NotNil(t, claimed)
+		assert.False(t, runner.RunNext(ctx))
+
+		_, err = testDB.Exec(`UPDATE job SET locked_until = NOW() - INTERVAL '1 second' WHERE job_id = $1`, job.JobID)
+		require.NoError(t, err)
+
+		require.True(t, runner.RunNext(ctx))
+
+		job, err = testService.GetJob(ctx, job.JobID)
+		require.NoError(t, err)
+		assert.Equal(t, services.JobStatusSucceeded, job.Status)
+		assert.Equal(t, 2, job.Attempts)
+
+		// Устаревшая попытка не перезаписывает статус и результат новой
+		err = testRepo.FailJob(ctx, job.JobID, claimed.Attempts, "stale worker")
+		assert.Error(t, err)
+		err = testRepo.UpdateJobProgress(ctx, job.JobID, claimed.Attempts, 0, 1, time.Minute)
+		assert.Error(t, err)
+
+		job, err = testService.GetJob(ctx, job.JobID)
+		require.NoError(t, err)
+		assert.Equal(t, services.JobStatusSucceeded, job.Status)
+		assert.Empty(t, job.Error)
+	})
+
+	t.Run("Job_FailedAfterMaxAttempts", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		job, err := testService.EnqueueMassDeactivation(ctx, dto.MassDeactivationRequest{TeamName: "frontend"})
+		require.NoError(t, err)
+
+		_, err = testDB.Exec(`UPDATE job SET status = 'RUNNING', attempts = 2, locked_until = NOW() - INTERVAL '1 second' WHERE job_id = $1`, job.JobID)
+		require.NoError(t, err)
+
+		assert.False(t, runner.RunNext(ctx))
+
+		job, err = testService.GetJob(ctx, job.JobID)
+		require.NoError(t, err)
+		assert.Equal(t, services.JobStatusFailed, job.Status)
+		assert.NotEmpty(t, job.Error)
+	})
+
+	t.Run("GetJob_NotFound", func(t *testing.T) {
+		clearTestData()
+
+		_, err := testService.GetJob(ctx, "missing")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrNotFound))
+	})
+}
@@ -0,0 +1,155 @@
+package integration
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+	services "pr_task/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserLeaveIntegration(t *testing.T) {
+	ctx := context.Background()
+	today := time.Now().Format("2006-01-02")
+	nextWeek := time.Now().AddDate(0, 0, 7).Format("2006-01-02")
+
+	t.Run("UserLeave_SkippedBySelection", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreateUserLeave(ctx, dto.CreateUserLeaveRequest{UserID: "u2", StartsOn: today, EndsOn: nextWeek, Reason: "vacation"})
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-500", PullRequestName: "Leave", AuthorID: "u1"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u3"}, pr.AssignedReviewers)
+
+		// Заменить u3 некем: u2 в отпуске, u4 неактивен
+		_, err = testService.ReassignReviewer(ctx, "pr-500", "u3")
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrNoCandidate))
+	})
+
+	t.Run("UserLeave_FutureLeaveDoesNotSkip", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		startsOn := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+		result, err := testService.CreateUserLeave(ctx, dto.CreateUserLeaveRequest{UserID: "u2", StartsOn: startsOn, EndsOn: nextWeek, AutoReassign: true})
+		require.NoError(t, err)
+		assert.Nil(t, result.Leave.ReassignedAt)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-501", PullRequestName: "Future", AuthorID: "u1"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+	})
+
+	t.Run("UserLeave_AutoReassignOnCreate", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		reviewerCount := 1
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-502", PullRequestName: "Auto", AuthorID: "u1", ReviewerCount: &reviewerCount})
+		require.NoError(t, err)
+		reviewer := pr.AssignedReviewers[0]
+
+		result, err := testService.CreateUserLeave(ctx, dto.CreateUserLeaveRequest{UserID: reviewer, StartsOn: today, EndsOn: nextWeek, AutoReassign: true})
+		require.NoError(t, err)
+		assert.NotNil(t, result.Leave.ReassignedAt)
+		require.Len(t, result.Reassignments, 1)
+		assert.Equal(t, reviewer, result.Reassignments[0].OldReviewerID)
+
+		updated, err := testRepo.GetPR(ctx, "pr-502")
+		require.NoError(t, err)
+		require.Len(t, updated.AssignedReviewers, 1)
+		assert.NotEqual(t, reviewer, updated.AssignedReviewers[0])
+	})
+
+	t.Run("UserLeave_RunnerReassignsStartedLeaves", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+		runner := services.NewJobRunner(testRepo, services.JobRunnerConfig{})
+
+		reviewerCount := 1
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-503", PullRequestName: "Runner", AuthorID: "u1", ReviewerCount: &reviewerCount})
+		require.NoError(t, err)
+		reviewer := pr.AssignedReviewers[0]
+
+		// Период, который начался без участия API, как если бы наступила его дата
+		err = testRepo.CreateUserLeave(ctx, &models.UserLeave{UserID: reviewer, StartsOn: today, EndsOn: nextWeek, AutoReassign: true})
+		require.NoError(t, err)
+
+		processed, err := runner.ReassignStartedLeaves(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, processed)
+
+		processed, err = runner.ReassignStartedLeaves(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, processed)
+
+		updated, err := testRepo.GetPR(ctx, "pr-503")
+		require.NoError(t, err)
+		assert.NotContains(t, updated.AssignedReviewers, reviewer)
+	})
+
+	t.Run("UserLeave_Validation", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreateUserLeave(ctx, dto.CreateUserLeaveRequest{UserID: "u2", StartsOn: nextWeek, EndsOn: today})
+		assert.True(t, errors.Is(err, errors.ErrInvalidLeavePeriod))
+
+		_, err = testService.CreateUserLeave(ctx, dto.CreateUserLeaveRequest{UserID: "u2", StartsOn: "01.07.2025", EndsOn: nextWeek})
+		assert.True(t, errors.Is(err, errors.ErrInvalidLeavePeriod))
+
+		_, err = testService.CreateUserLeave(ctx, dto.CreateUserLeaveRequest{UserID: "nonexistent", StartsOn: today, EndsOn: nextWeek})
+		assert.True(t, errors.Is(err, errors.ErrNotFound))
+
+		_, err = testService.CreateUserLeave(ctx, dto.CreateUserLeaveRequest{UserID: "u2", StartsOn: today, EndsOn: nextWeek})
+		require.NoError(t, err)
+		_, err = testService.CreateUserLeave(ctx, dto.CreateUserLeaveRequest{UserID: "u2", StartsOn: nextWeek, EndsOn: nextWeek})
+		assert.True(t, errors.Is(err, errors.ErrLeaveOverlap))
+
+		// Пересечение отклоняет и сама БД, даже без проверки в сервисе
+		err = testRepo.CreateUserLeave(ctx, &models.UserLeave{UserID: "u2", StartsOn: nextWeek, EndsOn: nextWeek})
+		require.Error(t, err)
+		assert.Equal(t, "leave overlaps", err.Error())
+		err = testRepo.CreateUserLeave(ctx, &models.UserLeave{UserID: "u3", StartsOn: nextWeek, EndsOn: nextWeek})
+		require.NoError(t, err)
+	})
+
+	t.Run("UserLeave_ListAndDelete", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		created, err := testService.CreateUserLeave(ctx, dto.CreateUserLeaveRequest{UserID: "u2", StartsOn: today, EndsOn: nextWeek, Reason: "conference"})
+		require.NoError(t, err)
+
+		leaves, err := testService.GetUserLeaves(ctx, "u2")
+		require.NoError(t, err)
+		require.Len(t, leaves.Leaves, 1)
+		assert.Equal(t, today, leaves.Leaves[0].StartsOn)
+		assert.Equal(t, "conference", leaves.Leaves[0].Reason)
+
+		_, err = testService.DeleteUserLeave(ctx, created.Leave.LeaveID)
+		require.NoError(t, err)
+
+		leaves, err = testService.GetUserLeaves(ctx, "u2")
+		require.NoError(t, err)
+		assert.Empty(t, leaves.Leaves)
+
+		_, err = testService.DeleteUserLeave(ctx, created.Leave.LeaveID)
+		assert.True(t, errors.Is(err, errors.ErrNotFound))
+	})
+}
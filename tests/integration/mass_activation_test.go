@@ -0,0 +1,63 @@
+package integration
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMassActivationIntegration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("MassActivate_Team", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		result, err := testService.MassActivateUsers(ctx, dto.MassActivationRequest{TeamName: "backend"})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.ActivatedUsers)
+		assert.Equal(t, []string{"u4"}, result.ActivatedUserIDs)
+	})
+
+	t.Run("MassActivate_UndoDeactivation", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-400", PullRequestName: "Undo", AuthorID: "u1"})
+		require.NoError(t, err)
+
+		deactivation, err := testService.MassDeactivateTeamUsers(ctx, dto.MassDeactivationRequest{TeamName: "backend", ExcludeUserIDs: []string{"u1", "u2"}})
+		require.NoError(t, err)
+		require.NotEmpty(t, deactivation.OperationID)
+		assert.Equal(t, 1, deactivation.DeactivatedUsers)
+
+		// Отмена активирует только u3, u4 остаётся неактивным
+		result, err := testService.MassActivateUsers(ctx, dto.MassActivationRequest{OperationID: deactivation.OperationID, RestoreReviewers: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u3"}, result.ActivatedUserIDs)
+		require.Len(t, result.RestoredPRs, 1)
+		assert.ElementsMatch(t, pr.AssignedReviewers, result.RestoredPRs[0].After)
+
+		u4, err := testRepo.GetUser(ctx, "u4")
+		require.NoError(t, err)
+		assert.False(t, u4.IsActive)
+
+		// Повторная отмена запрещена
+		_, err = testService.MassActivateUsers(ctx, dto.MassActivationRequest{OperationID: deactivation.OperationID})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "already undone")
+	})
+
+	t.Run("MassActivate_OperationNotFound", func(t *testing.T) {
+		clearTestData()
+
+		_, err := testService.MassActivateUsers(ctx, dto.MassActivationRequest{OperationID: "00000000-0000-0000-0000-000000000000"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+}
@@ -0,0 +1,266 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	"pr_task/internal/repository"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMassDeactivationIntegration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("MassDeactivate_ReplacesReviewers", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		// Кандидаты для u1 - только u2 и u4
+		_, err = testService.SetUserActive(ctx, "u4", true)
+		require.NoError(t, err)
+		_, err = testService.SetUserActive(ctx, "u3", false)
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-300", PullRequestName: "Mass", AuthorID: "u1"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u2", "u4"}, pr.AssignedReviewers)
+
+		_, err = testService.SetUserActive(ctx, "u3", true)
+		require.NoError(t, err)
+
+		// Деактивируется только u4, его место должен занять u3
+		result, err := testService.MassDeactivateTeamUsers(ctx, dto.MassDeactivationRequest{TeamName: "backend", ExcludeUserIDs: []string{"u1", "u2", "u3"}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.DeactivatedUsers)
+		assert.Equal(t, 1, result.UpdatedPRs)
+		assert.Empty(t, result.UnderstaffedPRs)
+		require.Len(t, result.PRChanges, 1)
+		assert.Equal(t, "pr-300", result.PRChanges[0].PRID)
+		assert.ElementsMatch(t, []string{"u2", "u4"}, result.PRChanges[0].Before)
+		assert.ElementsMatch(t, []string{"u2", "u3"}, result.PRChanges[0].After)
+
+		reviews, err := testService.GetUserReviewPRs(ctx, "u3")
+		require.NoError(t, err)
+		require.Len(t, reviews.PullRequests, 1)
+		assert.Equal(t, "pr-300", reviews.PullRequests[0].PullRequestID)
+	})
+
+	t.Run("MassDeactivate_NoReplacementAvailable", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-301", PullRequestName: "Mass", AuthorID: "u5"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u6"}, pr.AssignedReviewers)
+
+		// Заменить u6 некем - PR попадает в understaffed_prs
+		result, err := testService.MassDeactivateTeamUsers(ctx, dto.MassDeactivationRequest{TeamName: "frontend", ExcludeUserIDs: []string{"u5"}})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.DeactivatedUsers)
+		assert.Equal(t, []string{"pr-301"}, result.UnderstaffedPRs)
+		require.Len(t, result.PRChanges, 1)
+		assert.Empty(t, result.PRChanges[0].After)
+	})
+
+	t.Run("MassDeactivate_TeamNotFound", func(t *testing.T) {
+		clearTestData()
+
+		_, err := testService.MassDeactivateTeamUsers(ctx, dto.MassDeactivationRequest{TeamName: "nonexistent"})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrNotFound))
+	})
+
+	t.Run("WithTx_RollbackOnError", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		// Ошибка внутри транзакции откатывает уже выполненные изменения
+		err = testRepo.WithTx(ctx, func(repo repository.Repository) error {
+			if _, err := repo.MassDeactivateUsers(ctx, "backend", nil); err != nil {
+				return err
+			}
+			return fmt.Errorf("boom")
+		})
+		require.Error(t, err)
+
+		team, err := testService.GetTeam(ctx, "backend")
+		require.NoError(t, err)
+		for _, member := range team.Members {
+			if member.UserID != "u4" {
+				assert.True(t, member.IsActive, member.UserID)
+			}
+		}
+	})
+
+	t.Run("MassDeactivate_DryRun", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-302", PullRequestName: "Mass", AuthorID: "u1"})
+		require.NoError(t, err)
+
+		result, err := testService.MassDeactivateTeamUsers(ctx, dto.MassDeactivationRequest{TeamName: "backend", ExcludeUserIDs: []string{"u1"}, DryRun: true})
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		require.NotNil(t, result.Plan)
+		assert.ElementsMatch(t, []string{"u2", "u3"}, result.Plan.DeactivatedUserIDs)
+		require.Len(t, result.Plan.PRChanges, 1)
+		assert.ElementsMatch(t, pr.AssignedReviewers, result.Plan.PRChanges[0].Before)
+		assert.Empty(t, result.Plan.PRChanges[0].After)
+
+		// Пробный запуск ничего не меняет
+		user, err := testRepo.GetUser(ctx, "u2")
+		require.NoError(t, err)
+		assert.True(t, user.IsActive)
+
+		reviews, err := testService.GetUserReviewPRs(ctx, "u2")
+		require.NoError(t, err)
+		assert.Len(t, reviews.PullRequests, 1)
+	})
+
+	t.Run("MassDeactivate_DryRunKeepsRotation", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		strategy := "round_robin"
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", ReviewerStrategy: &strategy})
+		require.NoError(t, err)
+		_, err = testService.SetUserActive(ctx, "u4", true)
+		require.NoError(t, err)
+
+		reviewerCount := 1
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-303", PullRequestName: "Mass", AuthorID: "u1", ReviewerCount: &reviewerCount})
+		require.NoError(t, err)
+		require.Equal(t, []string{"u2"}, pr.AssignedReviewers)
+
+		// План строится по ротации, но курсор, пользователи и журнал операций не меняются
+		for i := 0; i < 2; i++ {
+			result, err := testService.MassDeactivateTeamUsers(ctx, dto.MassDeactivationRequest{TeamName: "backend", ExcludeUserIDs: []string{"u1", "u3", "u4"}, DryRun: true})
+			require.NoError(t, err)
+			require.NotNil(t, result.Plan)
+			assert.Equal(t, []string{"u2"}, result.Plan.DeactivatedUserIDs)
+			require.Len(t, result.Plan.PRChanges, 1)
+			assert.Equal(t, []string{"u3"}, result.Plan.PRChanges[0].After)
+		}
+
+		cursor, err := testRepo.GetRotationCursor(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, "u2", cursor)
+
+		var operations int
+		require.NoError(t, testDB.QueryRow(`SELECT COUNT(*) FROM mass_deactivation`).Scan(&operations))
+		assert.Equal(t, 0, operations)
+
+		user, err := testRepo.GetUser(ctx, "u2")
+		require.NoError(t, err)
+		assert.True(t, user.IsActive)
+	})
+	t.Run("MassDeactivate_UsesFallbackTeams", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", FallbackTeams: []string{"devops"}})
+		require.NoError(t, err)
+
+		reviewerCount := 1
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-304", PullRequestName: "Mass", AuthorID: "u1", ReviewerCount: &reviewerCount})
+		require.NoError(t, err)
+		require.Len(t, pr.AssignedReviewers, 1)
+
+		// В команде не остаётся кандидатов, замена берётся из devops
+		result, err := testService.MassDeactivateTeamUsers(ctx, dto.MassDeactivationRequest{TeamName: "backend", ExcludeUserIDs: []string{"u1"}})
+		require.NoError(t, err)
+		assert.Empty(t, result.UnderstaffedPRs)
+		require.Len(t, result.PRChanges, 1)
+		require.Len(t, result.PRChanges[0].After, 1)
+		replacement := result.PRChanges[0].After[0]
+		assert.Contains(t, []string{"u7", "u8"}, replacement)
+		assert.Equal(t, map[string]string{replacement: "devops"}, result.PRChanges[0].FallbackTeams)
+		require.NotNil(t, result.PRChanges[0].Explanations[replacement])
+		assert.Equal(t, "devops", result.PRChanges[0].Explanations[replacement].TeamName)
+
+		stored, err := testService.GetPullRequest(ctx, "pr-304")
+		require.NoError(t, err)
+		assert.Equal(t, []string{replacement}, stored.AssignedReviewers)
+		assert.Equal(t, map[string]string{replacement: "devops"}, stored.FallbackReviewers)
+	})
+
+	t.Run("MassDeactivate_UsesAuthorTeamLimits", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		maxReviewers := 1
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", MaxReviewers: &maxReviewers, FallbackTeams: []string{"devops"}})
+		require.NoError(t, err)
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "frontend", FallbackTeams: []string{"backend"}})
+		require.NoError(t, err)
+
+		// PR frontend получает обоих ревьюверов из backend
+		for userID, active := range map[string]bool{"u6": false, "u1": false} {
+			_, err = testService.SetUserActive(ctx, userID, active)
+			require.NoError(t, err)
+		}
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-305", PullRequestName: "Mass", AuthorID: "u5"})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+		_, err = testService.SetUserActive(ctx, "u1", true)
+		require.NoError(t, err)
+
+		// Предел backend в один ревьювер к PR frontend не применяется
+		result, err := testService.MassDeactivateTeamUsers(ctx, dto.MassDeactivationRequest{TeamName: "backend", ExcludeUserIDs: []string{"u1"}})
+		require.NoError(t, err)
+		assert.Empty(t, result.UnderstaffedPRs)
+		require.Len(t, result.PRChanges, 1)
+		after := result.PRChanges[0].After
+		require.Len(t, after, 2)
+		assert.Contains(t, after, "u1")
+	})
+
+	t.Run("MassDeactivate_KeepsFallbackAttribution", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "frontend", FallbackTeams: []string{"backend"}})
+		require.NoError(t, err)
+
+		for userID, active := range map[string]bool{"u6": false, "u1": false} {
+			_, err = testService.SetUserActive(ctx, userID, active)
+			require.NoError(t, err)
+		}
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-306", PullRequestName: "Mass", AuthorID: "u5"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"u2": "backend", "u3": "backend"}, pr.FallbackReviewers)
+		_, err = testService.SetUserActive(ctx, "u1", true)
+		require.NoError(t, err)
+
+		// u2 остаётся ревьювером из запасной команды, замена u3 для PR frontend тоже из запасной
+		expected := map[string]string{"u1": "backend", "u2": "backend"}
+		plan, err := testService.MassDeactivateTeamUsers(ctx, dto.MassDeactivationRequest{TeamName: "backend", ExcludeUserIDs: []string{"u1", "u2"}, DryRun: true})
+		require.NoError(t, err)
+		require.NotNil(t, plan.Plan)
+		require.Len(t, plan.Plan.PRChanges, 1)
+		assert.Equal(t, expected, plan.Plan.PRChanges[0].FallbackTeams)
+
+		result, err := testService.MassDeactivateTeamUsers(ctx, dto.MassDeactivationRequest{TeamName: "backend", ExcludeUserIDs: []string{"u1", "u2"}})
+		require.NoError(t, err)
+		require.Len(t, result.PRChanges, 1)
+		assert.ElementsMatch(t, []string{"u1", "u2"}, result.PRChanges[0].After)
+		assert.Equal(t, expected, result.PRChanges[0].FallbackTeams)
+
+		stored, err := testService.GetPullRequest(ctx, "pr-306")
+		require.NoError(t, err)
+		assert.Equal(t, expected, stored.FallbackReviewers)
+	})
+}
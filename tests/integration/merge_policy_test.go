@@ -0,0 +1,149 @@
+package integration
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePolicyIntegration(t *testing.T) {
+	ctx := context.Background()
+
+	intPtr := func(v int) *int { return &v }
+	boolPtr := func(v bool) *bool { return &v }
+
+	t.Run("MergeBlocked_ListsUnmetConditions", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{
+			TeamName:                "backend",
+			RequiredApprovals:       intPtr(1),
+			BlockOnChangesRequested: boolPtr(true),
+			RequireLeadApproval:     boolPtr(true),
+		})
+		require.NoError(t, err)
+
+		_, err = testDB.Exec(`UPDATE "user" SET is_lead = true WHERE user_id = 'u3'`)
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-500", PullRequestName: "Gate", AuthorID: "u1"})
+		require.NoError(t, err)
+
+		_, err = testService.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-500", UserID: "u2", State: "CHANGES_REQUESTED"})
+		require.NoError(t, err)
+
+		_, err = testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "pr-500"})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, errors.ErrMergeBlocked))
+
+		var blocked *errors.MergeBlockedError
+		require.True(t, errors.As(err, &blocked))
+		assert.ElementsMatch(t, []string{
+			"changes requested by u2",
+			"0 of 1 required approvals",
+			"no approval from a team lead",
+		}, blocked.Conditions)
+
+		pr, err := testRepo.GetPR(ctx, "pr-500")
+		require.NoError(t, err)
+		assert.Equal(t, "OPEN", pr.Status)
+
+		// Тимлид одобряет, u2 снимает запрос изменений
+		_, err = testService.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-500", UserID: "u3", State: "APPROVED"})
+		require.NoError(t, err)
+		_, err = testService.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-500", UserID: "u2", State: "COMMENTED"})
+		require.NoError(t, err)
+
+		merged, err := testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "pr-500", MergedBy: "u1"})
+		require.NoError(t, err)
+		assert.Equal(t, "MERGED", merged.Status)
+		assert.False(t, merged.ForceMerged)
+		assert.Empty(t, merged.BypassedConditions)
+	})
+
+	t.Run("LeadApproval_RequiresAuthorTeamLead", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{
+			TeamName:            "backend",
+			RequireLeadApproval: boolPtr(true),
+			FallbackTeams:       []string{"devops"},
+		})
+		require.NoError(t, err)
+
+		// В backend нет активных кандидатов, ревьювер назначается из devops, где оба участника - тимлиды
+		for _, userID := range []string{"u2", "u3"} {
+			_, err = testService.SetUserActive(ctx, userID, false)
+			require.NoError(t, err)
+		}
+		_, err = testDB.Exec(`UPDATE "user" SET is_lead = true WHERE user_id IN ('u7', 'u8')`)
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-503", PullRequestName: "Gate", AuthorID: "u1", ReviewerCount: intPtr(1)})
+		require.NoError(t, err)
+		require.Len(t, pr.AssignedReviewers, 1)
+		lead := pr.AssignedReviewers[0]
+		require.Equal(t, "devops", pr.FallbackReviewers[lead])
+
+		_, err = testService.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-503", UserID: lead, State: "APPROVED"})
+		require.NoError(t, err)
+
+		_, err = testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "pr-503"})
+		var blocked *errors.MergeBlockedError
+		require.True(t, errors.As(err, &blocked))
+		assert.Equal(t, []string{"no approval from a team lead"}, blocked.Conditions)
+	})
+
+	t.Run("ForceMerge_RecordsBypassedConditions", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", RequiredApprovals: intPtr(2)})
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-501", PullRequestName: "Hotfix", AuthorID: "u1"})
+		require.NoError(t, err)
+
+		merged, err := testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "pr-501", Force: true, MergedBy: "admin"})
+		require.NoError(t, err)
+		assert.Equal(t, "MERGED", merged.Status)
+
+		pr, err := testRepo.GetPR(ctx, "pr-501")
+		require.NoError(t, err)
+		assert.Equal(t, "MERGED", pr.Status)
+		assert.Equal(t, "admin", pr.MergedBy)
+		assert.True(t, pr.ForceMerged)
+		assert.Equal(t, []string{"0 of 2 required approvals"}, pr.BypassedConditions)
+	})
+
+	t.Run("DefaultPolicy_AllowsMerge", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-502", PullRequestName: "Plain", AuthorID: "u5"})
+		require.NoError(t, err)
+
+		merged, err := testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "pr-502"})
+		require.NoError(t, err)
+		assert.Equal(t, "MERGED", merged.Status)
+	})
+
+	t.Run("RequiredApprovals_CannotExceedMaxReviewers", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", RequiredApprovals: intPtr(3)})
+		assert.True(t, errors.Is(err, errors.ErrInvalidSettings))
+	})
+}
@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"context"
+	"pr_task/internal/migrations"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations_AllApplied(t *testing.T) {
+	ctx := context.Background()
+
+	migrator, err := migrations.NewMigrator(testDB)
+	require.NoError(t, err)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	for _, status := range statuses {
+		assert.NotNil(t, status.AppliedAt, "migration %d_%s is not applied", status.Version, status.Name)
+	}
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, applied)
+}
+
+func TestMigrations_DownAndUpAgain(t *testing.T) {
+	clearTestData()
+	ctx := context.Background()
+
+	migrator, err := migrations.NewMigrator(testDB)
+	require.NoError(t, err)
+
+	reverted, err := migrator.Down(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, reverted)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, statuses[len(statuses)-1].AppliedAt)
+	assert.Nil(t, statuses[len(statuses)-2].AppliedAt)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, applied)
+
+	_, err = testDB.ExecContext(ctx, "SELECT assigned_by FROM pr_reviewer LIMIT 1")
+	assert.NoError(t, err)
+}
+
+// TestMigrations_ConcurrentUp проверяет, что реплики, стартующие одновременно, не применяют миграции дважды
+func TestMigrations_ConcurrentUp(t *testing.T) {
+	ctx := context.Background()
+
+	migrator, err := migrations.NewMigrator(testDB)
+	require.NoError(t, err)
+
+	_, err = migrator.Down(ctx, 1)
+	require.NoError(t, err)
+
+	results := make(chan int, 3)
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			applied, err := migrator.Up(ctx)
+			results <- applied
+			errs <- err
+		}()
+	}
+
+	total := 0
+	for i := 0; i < 3; i++ {
+		total += <-results
+		require.NoError(t, <-errs)
+	}
+	assert.Equal(t, 1, total)
+}
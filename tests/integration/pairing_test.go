@@ -0,0 +1,139 @@
+package integration
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewerPairingIntegration(t *testing.T) {
+	ctx := context.Background()
+
+	setLookback := func(t *testing.T, teamName string, lookback int) {
+		_, err := testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: teamName, PairingLookback: &lookback})
+		require.NoError(t, err)
+	}
+
+	createPR := func(t *testing.T, prID, authorID string, requiredTags ...string) *dto.PullRequest {
+		reviewerCount := 1
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{
+			PullRequestID:   prID,
+			PullRequestName: "Pairing " + prID,
+			AuthorID:        authorID,
+			ReviewerCount:   &reviewerCount,
+			RequiredTags:    requiredTags,
+		})
+		require.NoError(t, err)
+		require.Len(t, pr.AssignedReviewers, 1)
+		return pr
+	}
+
+	t.Run("AuthorHistory_ReturnsLastPRs", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		first := createPR(t, "pr-1000", "u1")
+		second := createPR(t, "pr-1001", "u1")
+		third := createPR(t, "pr-1002", "u1")
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-1003", PullRequestName: "Draft", AuthorID: "u1", IsDraft: true})
+		require.NoError(t, err)
+
+		history, err := testRepo.GetAuthorReviewHistory(ctx, "u1", "", 2)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, "pr-1002", history[0].PRID)
+		assert.Equal(t, third.AssignedReviewers[0], history[0].ReviewerID)
+		assert.Equal(t, "pr-1001", history[1].PRID)
+		assert.Equal(t, second.AssignedReviewers[0], history[1].ReviewerID)
+		assert.Equal(t, "create", history[1].AssignedBy)
+
+		history, err = testRepo.GetAuthorReviewHistory(ctx, "u1", "pr-1002", 2)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, "pr-1001", history[0].PRID)
+		assert.Equal(t, "pr-1000", history[1].PRID)
+		assert.Equal(t, first.AssignedReviewers[0], history[1].ReviewerID)
+	})
+
+	t.Run("AuthorHistory_KeepsRemovedReviewers", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		oldReviewer := createPR(t, "pr-1004", "u1").AssignedReviewers[0]
+		result, err := testService.ReassignReviewer(ctx, "pr-1004", oldReviewer)
+		require.NoError(t, err)
+
+		// Снятый ревьювер остаётся в истории вместе с назначенным вместо него
+		history, err := testRepo.GetAuthorReviewHistory(ctx, "u1", "", 1)
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		assert.Equal(t, oldReviewer, history[0].ReviewerID)
+		assert.Equal(t, "create", history[0].AssignedBy)
+		assert.Equal(t, result.ReplacedBy, history[1].ReviewerID)
+		assert.Equal(t, "reassign", history[1].AssignedBy)
+	})
+
+	t.Run("CreatePR_AvoidsRecentReviewers", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		setLookback(t, "backend", 1)
+
+		// Закрытые PR не дают нагрузки, поэтому выбор определяется только историей пар
+		previous := ""
+		for _, prID := range []string{"pr-1010", "pr-1011", "pr-1012", "pr-1013"} {
+			pr := createPR(t, prID, "u1")
+			reviewer := pr.AssignedReviewers[0]
+			assert.Contains(t, []string{"u2", "u3"}, reviewer)
+			assert.NotEqual(t, previous, reviewer, "PR %s", prID)
+			previous = reviewer
+
+			_, err := testService.ClosePullRequest(ctx, prID)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("RequiredTags_OutrankPairing", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		setLookback(t, "backend", 5)
+		_, err = testService.SetUserTags(ctx, dto.SetUserTagsRequest{UserID: "u3", Tags: []string{"postgres"}})
+		require.NoError(t, err)
+
+		for _, prID := range []string{"pr-1020", "pr-1021"} {
+			pr := createPR(t, prID, "u1", "postgres")
+			assert.Equal(t, []string{"u3"}, pr.AssignedReviewers)
+			_, err := testService.ClosePullRequest(ctx, prID)
+			require.NoError(t, err)
+		}
+
+		// Без требуемых тегов недавний ревьювер уступает другому участнику
+		pr := createPR(t, "pr-1022", "u1")
+		assert.Equal(t, []string{"u2"}, pr.AssignedReviewers)
+	})
+
+	t.Run("PairingLookback_Validation", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		for _, lookback := range []int{-1, 101} {
+			_, err := testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", PairingLookback: &lookback})
+			assert.True(t, errors.Is(err, errors.ErrInvalidSettings), "lookback %d", lookback)
+		}
+
+		setLookback(t, "backend", 10)
+		settings, err := testService.GetTeamSettings(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, 10, settings.PairingLookback)
+	})
+}
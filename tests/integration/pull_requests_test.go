@@ -2,6 +2,11 @@ package integration
 
 import (
 	"context"
+	"fmt"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	"slices"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,7 +22,7 @@ func TestPullRequestIntegration(t *testing.T) {
 		require.NoError(t, err)
 
 		// Создаем PR
-		pr, err := testService.CreatePullRequest(ctx, "pr-101", "Feature X", "u1")
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-101", PullRequestName: "Feature X", AuthorID: "u1"})
 		require.NoError(t, err)
 
 		assert.Equal(t, "pr-101", pr.PullRequestID)
@@ -32,7 +37,7 @@ func TestPullRequestIntegration(t *testing.T) {
 	t.Run("CreatePR_AuthorNotFound", func(t *testing.T) {
 		clearTestData()
 
-		_, err := testService.CreatePullRequest(ctx, "pr-102", "Feature Y", "nonexistent")
+		_, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-102", PullRequestName: "Feature Y", AuthorID: "nonexistent"})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
 	})
@@ -43,11 +48,11 @@ func TestPullRequestIntegration(t *testing.T) {
 		require.NoError(t, err)
 
 		// Создаем PR первый раз
-		_, err = testService.CreatePullRequest(ctx, "pr-103", "Feature Z", "u1")
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-103", PullRequestName: "Feature Z", AuthorID: "u1"})
 		require.NoError(t, err)
 
 		// Пытаемся создать PR с тем же ID
-		_, err = testService.CreatePullRequest(ctx, "pr-103", "Feature Z", "u1")
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-103", PullRequestName: "Feature Z", AuthorID: "u1"})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "PR already exists")
 	})
@@ -58,18 +63,18 @@ func TestPullRequestIntegration(t *testing.T) {
 		require.NoError(t, err)
 
 		// Создаем PR
-		pr, err := testService.CreatePullRequest(ctx, "pr-104", "Feature A", "u1")
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-104", PullRequestName: "Feature A", AuthorID: "u1"})
 		require.NoError(t, err)
 		assert.Equal(t, "OPEN", pr.Status)
 
 		// Мержим PR
-		mergedPR, err := testService.MergePullRequest(ctx, "pr-104")
+		mergedPR, err := testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "pr-104"})
 		require.NoError(t, err)
 		assert.Equal(t, "MERGED", mergedPR.Status)
 		assert.NotNil(t, mergedPR.MergedAt)
 
 		// Пытаемся мержить еще раз (идемпотентность)
-		mergedPR2, err := testService.MergePullRequest(ctx, "pr-104")
+		mergedPR2, err := testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "pr-104"})
 		require.NoError(t, err)
 		assert.Equal(t, "MERGED", mergedPR2.Status)
 	})
@@ -77,18 +82,276 @@ func TestPullRequestIntegration(t *testing.T) {
 	t.Run("MergePR_NotFound", func(t *testing.T) {
 		clearTestData()
 
-		_, err := testService.MergePullRequest(ctx, "nonexistent")
+		_, err := testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "nonexistent"})
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
 	})
 
+	t.Run("ClosePR_HiddenFromReviewsAndStats", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-120", PullRequestName: "Abandoned", AuthorID: "u1"})
+		require.NoError(t, err)
+		require.NotEmpty(t, pr.AssignedReviewers)
+		reviewer := pr.AssignedReviewers[0]
+
+		closedPR, err := testService.ClosePullRequest(ctx, "pr-120")
+		require.NoError(t, err)
+		assert.Equal(t, "CLOSED", closedPR.Status)
+		assert.NotNil(t, closedPR.ClosedAt)
+
+		// Повторное закрытие идемпотентно
+		closedPR, err = testService.ClosePullRequest(ctx, "pr-120")
+		require.NoError(t, err)
+		assert.Equal(t, "CLOSED", closedPR.Status)
+
+		reviews, err := testService.GetUserReviewPRs(ctx, reviewer)
+		require.NoError(t, err)
+		assert.Empty(t, reviews.PullRequests)
+
+		stats, err := testService.GetOverallStats(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, stats.OpenPRs)
+		assert.Equal(t, 1, stats.ClosedPRs)
+
+		_, err = testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "pr-120"})
+		assert.True(t, errors.Is(err, errors.ErrPRClosed))
+
+		_, err = testService.ReassignReviewer(ctx, "pr-120", reviewer)
+		assert.True(t, errors.Is(err, errors.ErrPRClosed))
+	})
+
+	t.Run("ClosePR_MergedRejected", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-121", PullRequestName: "Done", AuthorID: "u1"})
+		require.NoError(t, err)
+		_, err = testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "pr-121"})
+		require.NoError(t, err)
+
+		_, err = testService.ClosePullRequest(ctx, "pr-121")
+		assert.True(t, errors.Is(err, errors.ErrPRMerged))
+
+		_, err = testService.ReopenPullRequest(ctx, "pr-121")
+		assert.True(t, errors.Is(err, errors.ErrPRMerged))
+	})
+
+	t.Run("ReopenPR_ReplacesInactiveReviewers", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-122", PullRequestName: "Later", AuthorID: "u1"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+
+		_, err = testService.ClosePullRequest(ctx, "pr-122")
+		require.NoError(t, err)
+
+		// Пока PR закрыт, u2 уходит, а u4 возвращается
+		_, err = testService.SetUserActive(ctx, "u2", false)
+		require.NoError(t, err)
+		_, err = testService.SetUserActive(ctx, "u4", true)
+		require.NoError(t, err)
+
+		reopened, err := testService.ReopenPullRequest(ctx, "pr-122")
+		require.NoError(t, err)
+		assert.Equal(t, "OPEN", reopened.Status)
+		assert.Nil(t, reopened.ClosedAt)
+		assert.ElementsMatch(t, []string{"u3", "u4"}, reopened.AssignedReviewers)
+
+		stored, err := testRepo.GetPR(ctx, "pr-122")
+		require.NoError(t, err)
+		assert.Equal(t, "OPEN", stored.Status)
+		assert.Nil(t, stored.ClosedAt)
+		assert.ElementsMatch(t, []string{"u3", "u4"}, stored.AssignedReviewers)
+	})
+
+	t.Run("DraftPR_AssignsReviewersOnMarkReady", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-130", PullRequestName: "WIP", AuthorID: "u1", IsDraft: true})
+		require.NoError(t, err)
+		assert.Equal(t, "DRAFT", pr.Status)
+		assert.Empty(t, pr.AssignedReviewers)
+
+		reviews, err := testService.GetUserReviewPRs(ctx, "u2")
+		require.NoError(t, err)
+		assert.Empty(t, reviews.PullRequests)
+
+		_, err = testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "pr-130"})
+		assert.True(t, errors.Is(err, errors.ErrPRDraft))
+
+		// Пока PR был черновиком, u4 вернулся в команду, а u2 ушёл
+		_, err = testService.SetUserActive(ctx, "u4", true)
+		require.NoError(t, err)
+		_, err = testService.SetUserActive(ctx, "u2", false)
+		require.NoError(t, err)
+
+		ready, err := testService.MarkPullRequestReady(ctx, dto.MarkReadyRequest{PullRequestID: "pr-130"})
+		require.NoError(t, err)
+		assert.Equal(t, "OPEN", ready.Status)
+		assert.ElementsMatch(t, []string{"u3", "u4"}, ready.AssignedReviewers)
+
+		stored, err := testRepo.GetPR(ctx, "pr-130")
+		require.NoError(t, err)
+		assert.Equal(t, "OPEN", stored.Status)
+		assert.ElementsMatch(t, []string{"u3", "u4"}, stored.AssignedReviewers)
+
+		// Повторный вызов ничего не меняет
+		again, err := testService.MarkPullRequestReady(ctx, dto.MarkReadyRequest{PullRequestID: "pr-130"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, ready.AssignedReviewers, again.AssignedReviewers)
+	})
+
+	t.Run("DraftPR_MarkReadyWithReviewerCount", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-131", PullRequestName: "WIP", AuthorID: "u1", IsDraft: true})
+		require.NoError(t, err)
+
+		tooMany := 5
+		_, err = testService.MarkPullRequestReady(ctx, dto.MarkReadyRequest{PullRequestID: "pr-131", ReviewerCount: &tooMany})
+		assert.True(t, errors.Is(err, errors.ErrInvalidReviewerCount))
+
+		stored, err := testRepo.GetPR(ctx, "pr-131")
+		require.NoError(t, err)
+		assert.Equal(t, "DRAFT", stored.Status)
+
+		one := 1
+		ready, err := testService.MarkPullRequestReady(ctx, dto.MarkReadyRequest{PullRequestID: "pr-131", ReviewerCount: &one})
+		require.NoError(t, err)
+		assert.Len(t, ready.AssignedReviewers, 1)
+	})
+
+	t.Run("DraftPR_MarkReadyNotFound", func(t *testing.T) {
+		clearTestData()
+
+		_, err := testService.MarkPullRequestReady(ctx, dto.MarkReadyRequest{PullRequestID: "nonexistent"})
+		assert.True(t, errors.Is(err, errors.ErrNotFound))
+	})
+
+	t.Run("SubmitReview_StoresVerdict", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-140", PullRequestName: "Review me", AuthorID: "u1"})
+		require.NoError(t, err)
+
+		reviews, err := testService.GetUserReviewPRs(ctx, "u2")
+		require.NoError(t, err)
+		require.Len(t, reviews.PullRequests, 1)
+		assert.Equal(t, "PENDING", reviews.PullRequests[0].ReviewState)
+		assert.Nil(t, reviews.PullRequests[0].ReviewedAt)
+
+		pr, err := testService.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-140", UserID: "u2", State: "CHANGES_REQUESTED"})
+		require.NoError(t, err)
+		require.Len(t, pr.Reviews, 2)
+
+		pr, err = testService.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-140", UserID: "u2", State: "APPROVED"})
+		require.NoError(t, err)
+		for _, review := range pr.Reviews {
+			if review.UserID == "u2" {
+				assert.Equal(t, "APPROVED", review.State)
+				assert.NotNil(t, review.ReviewedAt)
+			} else {
+				assert.Equal(t, "PENDING", review.State)
+			}
+		}
+
+		reviews, err = testService.GetUserReviewPRs(ctx, "u2")
+		require.NoError(t, err)
+		require.Len(t, reviews.PullRequests, 1)
+		assert.Equal(t, "APPROVED", reviews.PullRequests[0].ReviewState)
+		assert.NotNil(t, reviews.PullRequests[0].ReviewedAt)
+
+		// После замены u3 вердикт u2 сохраняется, а u4 начинает с PENDING
+		_, err = testService.SetUserActive(ctx, "u4", true)
+		require.NoError(t, err)
+		result, err := testService.ReassignReviewer(ctx, "pr-140", "u3")
+		require.NoError(t, err)
+		assert.Equal(t, "u4", result.ReplacedBy)
+
+		stored, err := testRepo.GetPR(ctx, "pr-140")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u2", "u4"}, stored.AssignedReviewers)
+		states := map[string]string{}
+		for _, review := range stored.Reviews {
+			states[review.UserID] = review.State
+		}
+		assert.Equal(t, map[string]string{"u2": "APPROVED", "u4": "PENDING"}, states)
+	})
+
+	t.Run("Reviewers_RecordAssignmentSource", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-142", PullRequestName: "Source", AuthorID: "u1"})
+		require.NoError(t, err)
+
+		_, err = testService.SetUserActive(ctx, "u4", true)
+		require.NoError(t, err)
+		_, err = testService.ReassignReviewer(ctx, "pr-142", "u3")
+		require.NoError(t, err)
+
+		pr, err := testRepo.GetPR(ctx, "pr-142")
+		require.NoError(t, err)
+		sources := map[string]string{}
+		for _, review := range pr.Reviews {
+			sources[review.UserID] = review.AssignedBy
+		}
+		assert.Equal(t, map[string]string{"u2": "create", "u4": "reassign"}, sources)
+
+		// Назначить несуществующего пользователя не даёт внешний ключ
+		err = testRepo.UpdatePRReviewers(ctx, "pr-142", []string{"u2", "ghost"}, "reassign")
+		require.Error(t, err)
+
+		pr, err = testRepo.GetPR(ctx, "pr-142")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u2", "u4"}, pr.AssignedReviewers)
+	})
+
+	t.Run("SubmitReview_Rejected", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-141", PullRequestName: "Review me", AuthorID: "u1"})
+		require.NoError(t, err)
+
+		_, err = testService.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-141", UserID: "u2", State: "LGTM"})
+		assert.True(t, errors.Is(err, errors.ErrInvalidReviewState))
+
+		_, err = testService.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-141", UserID: "u1", State: "APPROVED"})
+		assert.True(t, errors.Is(err, errors.ErrNotAssigned))
+
+		_, err = testService.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "missing", UserID: "u2", State: "APPROVED"})
+		assert.True(t, errors.Is(err, errors.ErrNotFound))
+
+		_, err = testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "pr-141"})
+		require.NoError(t, err)
+
+		_, err = testService.SubmitReview(ctx, dto.SubmitReviewRequest{PullRequestID: "pr-141", UserID: "u2", State: "APPROVED"})
+		assert.True(t, errors.Is(err, errors.ErrPRMerged))
+	})
+
 	t.Run("ReassignReviewer_Success", func(t *testing.T) {
 		clearTestData()
 		err := setupTestData(ctx)
 		require.NoError(t, err)
 
 		// Создаем PR
-		pr, err := testService.CreatePullRequest(ctx, "pr-105", "Feature B", "u1")
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-105", PullRequestName: "Feature B", AuthorID: "u1"})
 		require.NoError(t, err)
 		originalReviewers := pr.AssignedReviewers
 		assert.Len(t, originalReviewers, 2)
@@ -109,7 +372,7 @@ func TestPullRequestIntegration(t *testing.T) {
 		require.NoError(t, err)
 
 		// Создаем PR
-		_, err = testService.CreatePullRequest(ctx, "pr-106", "Feature C", "u1")
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-106", PullRequestName: "Feature C", AuthorID: "u1"})
 		require.NoError(t, err)
 
 		// Пытаемся переназначить не назначенного ревьювера
@@ -124,10 +387,10 @@ func TestPullRequestIntegration(t *testing.T) {
 		require.NoError(t, err)
 
 		// Создаем и мержим PR
-		pr, err := testService.CreatePullRequest(ctx, "pr-107", "Feature D", "u1")
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-107", PullRequestName: "Feature D", AuthorID: "u1"})
 		require.NoError(t, err)
 
-		_, err = testService.MergePullRequest(ctx, "pr-107")
+		_, err = testService.MergePullRequest(ctx, dto.MergePullRequestRequest{PullRequestID: "pr-107"})
 		require.NoError(t, err)
 
 		// Пытаемся переназначить ревьювера в замерженном PR
@@ -135,4 +398,84 @@ func TestPullRequestIntegration(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "PR is merged")
 	})
+
+	t.Run("CreatePR_LeastLoadedReviewers", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		// Активируем u4, чтобы у автора было три кандидата
+		_, err = testService.SetUserActive(ctx, "u4", true)
+		require.NoError(t, err)
+
+		first, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-108", PullRequestName: "Feature E", AuthorID: "u1"})
+		require.NoError(t, err)
+		require.Len(t, first.AssignedReviewers, 2)
+
+		// Второй PR должен достаться наименее загруженному кандидату
+		var idle string
+		for _, candidate := range []string{"u2", "u3", "u4"} {
+			if !slices.Contains(first.AssignedReviewers, candidate) {
+				idle = candidate
+			}
+		}
+
+		second, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-109", PullRequestName: "Feature F", AuthorID: "u1"})
+		require.NoError(t, err)
+		assert.Contains(t, second.AssignedReviewers, idle)
+	})
+
+	t.Run("CreatePR_RoundRobinRotation", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		strategy := "round_robin"
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", ReviewerStrategy: &strategy})
+		require.NoError(t, err)
+
+		_, err = testService.SetUserActive(ctx, "u4", true)
+		require.NoError(t, err)
+
+		// Ротация идёт по порядку u2 -> u3 -> u4 -> u2
+		reviewerCount := 1
+		var assigned []string
+		for _, prID := range []string{"pr-110", "pr-111", "pr-112", "pr-113"} {
+			pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: prID, PullRequestName: "Rotation", AuthorID: "u1", ReviewerCount: &reviewerCount})
+			require.NoError(t, err)
+			assigned = append(assigned, pr.AssignedReviewers...)
+		}
+		assert.Equal(t, []string{"u2", "u3", "u4", "u2"}, assigned)
+
+		// Деактивированный пользователь пропускается
+		_, err = testService.SetUserActive(ctx, "u3", false)
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-114", PullRequestName: "Rotation", AuthorID: "u1", ReviewerCount: &reviewerCount})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u4"}, pr.AssignedReviewers)
+
+		// Неудавшееся создание не сдвигает курсор
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-114", PullRequestName: "Rotation", AuthorID: "u1", ReviewerCount: &reviewerCount})
+		assert.True(t, errors.Is(err, errors.ErrPRExists))
+		cursor, err := testRepo.GetRotationCursor(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, "u4", cursor)
+
+		// Параллельные запросы получают ревьюверов строго по очереди ротации
+		var wg sync.WaitGroup
+		reviewers := make([]string, 4)
+		for i := range reviewers {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: fmt.Sprintf("pr-12%d", i), PullRequestName: "Rotation", AuthorID: "u1", ReviewerCount: &reviewerCount})
+				if assert.NoError(t, err) && assert.Len(t, pr.AssignedReviewers, 1) {
+					reviewers[i] = pr.AssignedReviewers[0]
+				}
+			}(i)
+		}
+		wg.Wait()
+		assert.ElementsMatch(t, []string{"u2", "u4", "u2", "u4"}, reviewers)
+	})
 }
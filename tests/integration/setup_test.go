@@ -7,6 +7,7 @@ import (
 	"log"
 	"os"
 	handlers "pr_task/internal/handler"
+	"pr_task/internal/migrations"
 	"pr_task/internal/repository"
 	services "pr_task/internal/service"
 	"testing"
@@ -64,7 +65,7 @@ func setup() error {
 		return fmt.Errorf("failed to ping test database: %v", err)
 	}
 
-	// Создаем таблицы из init.sql
+	// Создаем таблицы миграциями
 	if err := createTestTables(); err != nil {
 		return fmt.Errorf("failed to create test tables: %v", err)
 	}
@@ -72,59 +73,22 @@ func setup() error {
 	// Инициализируем зависимости
 	testRepo = repository.NewPostgresRepository(testDB)
 	testService = services.NewService(testRepo)
-	testHandler = handlers.NewHandler(testService)
+	testHandler = handlers.NewHandler(testService, "")
 
 	log.Println("Test database setup completed successfully")
 	return nil
 }
 
-// createTestTables создает таблицы для тестов
+// createTestTables создает таблицы для тестов теми же миграциями, что применяет сервис
 func createTestTables() error {
-	queries := []string{
-		// Создаем ENUM тип если не существует
-		`DO $$ BEGIN
-			CREATE TYPE pr_status AS ENUM ('OPEN', 'MERGED');
-		EXCEPTION
-			WHEN duplicate_object THEN null;
-		END $$;`,
-
-		// Таблица команд
-		`CREATE TABLE IF NOT EXISTS team (
-			team_name TEXT PRIMARY KEY
-		)`,
-
-		// Таблица пользователей
-		`CREATE TABLE IF NOT EXISTS "user" (
-			user_id   TEXT PRIMARY KEY,
-			username  TEXT NOT NULL,
-			team_name TEXT NOT NULL REFERENCES team(team_name) ON DELETE CASCADE,
-			is_active BOOLEAN NOT NULL DEFAULT true
-		)`,
-
-		// Таблица Pull Requests
-		`CREATE TABLE IF NOT EXISTS pull_request (
-			pull_request_id   TEXT PRIMARY KEY,
-			pull_request_name TEXT NOT NULL,
-			author_id         TEXT NOT NULL REFERENCES "user"(user_id),
-			status            pr_status NOT NULL DEFAULT 'OPEN',
-			assigned_reviewers TEXT[] NOT NULL DEFAULT '{}',
-			created_at        TIMESTAMPTZ DEFAULT NOW(),
-			merged_at         TIMESTAMPTZ
-		)`,
-
-		// Создаем индексы
-		`CREATE INDEX IF NOT EXISTS idx_user_team_name ON "user"(team_name)`,
-		`CREATE INDEX IF NOT EXISTS idx_pr_author_id ON pull_request(author_id)`,
-		`CREATE INDEX IF NOT EXISTS idx_pr_status ON pull_request(status)`,
-		`CREATE INDEX IF NOT EXISTS idx_pr_reviewers ON pull_request USING GIN (assigned_reviewers)`,
+	migrator, err := migrations.NewMigrator(testDB)
+	if err != nil {
+		return err
 	}
 
-	for _, query := range queries {
-		if _, err := testDB.Exec(query); err != nil {
-			return fmt.Errorf("failed to execute query: %s, error: %v", query, err)
-		}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		return err
 	}
-
 	return nil
 }
 
@@ -139,7 +103,14 @@ func cleanup() {
 // clearTestData очищает тестовые данные
 func clearTestData() {
 	queries := []string{
+		"DELETE FROM job",
+		"DELETE FROM reviewer_assignment",
+		"DELETE FROM pr_reviewer",
 		"DELETE FROM pull_request",
+		"DELETE FROM team_rotation",
+		"DELETE FROM mass_deactivation",
+		"DELETE FROM user_leave",
+		"DELETE FROM code_owner_rule",
 		"DELETE FROM \"user\"",
 		"DELETE FROM team",
 	}
@@ -0,0 +1,193 @@
+package integration
+
+import (
+	"context"
+	"pr_task/internal/dto"
+	errors "pr_task/internal/error"
+	models "pr_task/internal/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkillTagsIntegration(t *testing.T) {
+	ctx := context.Background()
+
+	setTags := func(t *testing.T, userID string, tags ...string) {
+		if tags == nil {
+			tags = []string{}
+		}
+		_, err := testService.SetUserTags(ctx, dto.SetUserTagsRequest{UserID: userID, Tags: tags})
+		require.NoError(t, err)
+	}
+
+	t.Run("SetUserTags_NormalizesAndLists", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		user, err := testService.SetUserTags(ctx, dto.SetUserTagsRequest{UserID: "u2", Tags: []string{" Postgres", "go", "GO"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"go", "postgres"}, user.Tags)
+		setTags(t, "u3", "go")
+
+		tags, err := testService.ListTags(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []dto.TagUsage{{Tag: "go", Users: 2}, {Tag: "postgres", Users: 1}}, tags.Tags)
+
+		users, err := testService.ListUsers(ctx, models.UserFilter{Tag: "postgres", Limit: 20})
+		require.NoError(t, err)
+		require.Len(t, users.Users, 1)
+		assert.Equal(t, "u2", users.Users[0].UserID)
+
+		for _, invalid := range [][]string{{""}, {"with space"}, {"-go"}} {
+			_, err := testService.SetUserTags(ctx, dto.SetUserTagsRequest{UserID: "u2", Tags: invalid})
+			assert.True(t, errors.Is(err, errors.ErrInvalidTags), "tags %v", invalid)
+		}
+
+		_, err = testService.SetUserTags(ctx, dto.SetUserTagsRequest{UserID: "ghost", Tags: []string{"go"}})
+		assert.True(t, errors.Is(err, errors.ErrNotFound))
+
+		// Пустой список удаляет теги
+		setTags(t, "u2")
+		user, err = testService.GetUser(ctx, "u2")
+		require.NoError(t, err)
+		assert.Empty(t, user.Tags)
+	})
+
+	t.Run("TeamMembers_CarryTags", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreateTeam(ctx, models.Team{TeamName: "data", Members: []models.TeamMember{
+			{UserID: "d1", Username: "Dana", IsActive: true, Tags: []string{"Spark", "python"}},
+			{UserID: "d2", Username: "Dmitry", IsActive: true},
+		}})
+		require.NoError(t, err)
+
+		// Участник без тегов в запросе сохраняет прежние
+		_, err = testService.UpdateTeamMembers(ctx, dto.UpdateTeamMembersRequest{TeamName: "data", Add: []models.TeamMember{
+			{UserID: "d1", Username: "Dana Scott", IsActive: true},
+		}})
+		require.NoError(t, err)
+
+		team, err := testService.GetTeam(ctx, "data")
+		require.NoError(t, err)
+		for _, member := range team.Members {
+			if member.UserID == "d1" {
+				assert.Equal(t, []string{"python", "spark"}, member.Tags)
+			} else {
+				assert.Empty(t, member.Tags)
+			}
+		}
+
+		_, err = testService.CreateTeam(ctx, models.Team{TeamName: "ml", Members: []models.TeamMember{
+			{UserID: "m1", Username: "Mia", IsActive: true, Tags: []string{"bad tag"}},
+		}})
+		assert.True(t, errors.Is(err, errors.ErrInvalidTags))
+	})
+
+	t.Run("CreatePR_PrefersTagsOverLoad", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		setTags(t, "u3", "postgres")
+
+		// Подходящий по тегам ревьювер выбирается, даже когда он загружен сильнее остальных
+		reviewerCount := 1
+		for _, prID := range []string{"pr-900", "pr-901", "pr-902"} {
+			pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{
+				PullRequestID:   prID,
+				PullRequestName: "Migration",
+				AuthorID:        "u1",
+				ReviewerCount:   &reviewerCount,
+				RequiredTags:    []string{"Postgres"},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"u3"}, pr.AssignedReviewers)
+			assert.Equal(t, []string{"postgres"}, pr.RequiredTags)
+		}
+
+		stored, err := testRepo.GetPR(ctx, "pr-900")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"postgres"}, stored.RequiredTags)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-903", PullRequestName: "Bad", AuthorID: "u1", RequiredTags: []string{"a b"}})
+		assert.True(t, errors.Is(err, errors.ErrInvalidTags))
+	})
+
+	t.Run("Selection_OrdersByOverlap", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreateTeam(ctx, models.Team{TeamName: "data", Members: []models.TeamMember{
+			{UserID: "d1", Username: "Dana", IsActive: true},
+			{UserID: "d2", Username: "Dmitry", IsActive: true, Tags: []string{"postgres"}},
+			{UserID: "d3", Username: "Diana", IsActive: true, Tags: []string{"go", "postgres"}},
+			{UserID: "d4", Username: "Denis", IsActive: true},
+		}})
+		require.NoError(t, err)
+
+		reviewerCount := 1
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{
+			PullRequestID:   "pr-904",
+			PullRequestName: "Storage",
+			AuthorID:        "d1",
+			ReviewerCount:   &reviewerCount,
+			RequiredTags:    []string{"go", "postgres"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"d3"}, pr.AssignedReviewers)
+
+		// При переназначении частичное совпадение важнее свободного кандидата без тегов
+		result, err := testService.ReassignReviewer(ctx, "pr-904", "d3")
+		require.NoError(t, err)
+		assert.Equal(t, "d2", result.ReplacedBy)
+	})
+
+	t.Run("RoundRobin_RotatesAcrossTagTiers", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreateTeam(ctx, models.Team{TeamName: "data", Members: []models.TeamMember{
+			{UserID: "d1", Username: "Dana", IsActive: true},
+			{UserID: "d2", Username: "Dmitry", IsActive: true, Tags: []string{"go"}},
+			{UserID: "d3", Username: "Diana", IsActive: true},
+			{UserID: "d4", Username: "Denis", IsActive: true, Tags: []string{"go"}},
+			{UserID: "d5", Username: "Daria", IsActive: true},
+		}})
+		require.NoError(t, err)
+
+		strategy, maxReviewers := "round_robin", 3
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "data", ReviewerStrategy: &strategy, MaxReviewers: &maxReviewers})
+		require.NoError(t, err)
+
+		// Оба уровня обходятся от одного курсора, поэтому участники без тегов чередуются, а не пропускаются
+		var untagged []string
+		for _, prID := range []string{"pr-905", "pr-906", "pr-907"} {
+			pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{
+				PullRequestID:   prID,
+				PullRequestName: "Rotation",
+				AuthorID:        "d1",
+				ReviewerCount:   &maxReviewers,
+				RequiredTags:    []string{"go"},
+			})
+			require.NoError(t, err)
+			require.Len(t, pr.AssignedReviewers, 3)
+			assert.ElementsMatch(t, []string{"d2", "d4"}, pr.AssignedReviewers[:2], "PR %s", prID)
+			untagged = append(untagged, pr.AssignedReviewers[2])
+		}
+		assert.Equal(t, []string{"d3", "d5", "d3"}, untagged)
+
+		// Без требуемых тегов ротация продолжается по всему составу после последнего выбранного
+		reviewerCount := 1
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-908", PullRequestName: "Rotation", AuthorID: "d1", ReviewerCount: &reviewerCount})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"d4"}, pr.AssignedReviewers)
+	})
+}
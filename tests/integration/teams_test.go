@@ -2,6 +2,7 @@ package integration
 
 import (
 	"context"
+	"pr_task/internal/dto"
 	models "pr_task/internal/model"
 	"testing"
 
@@ -57,6 +58,24 @@ func TestTeamIntegration(t *testing.T) {
 		assert.Contains(t, err.Error(), "team already exists")
 	})
 
+	t.Run("CreateTeam_MemberInOtherTeam", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		// u5 уже состоит в frontend, перевод возможен только явно
+		_, err = testService.CreateTeam(ctx, models.Team{
+			TeamName: "mobile",
+			Members:  []models.TeamMember{{UserID: "u5", Username: "Eve", IsActive: true}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "another team")
+
+		user, err := testRepo.GetUser(ctx, "u5")
+		require.NoError(t, err)
+		assert.Equal(t, "frontend", user.TeamName)
+	})
+
 	t.Run("GetTeam_Success", func(t *testing.T) {
 		// Очищаем данные перед тестом
 		clearTestData()
@@ -80,4 +99,230 @@ func TestTeamIntegration(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
 	})
+
+	t.Run("UpdateTeamSettings_Success", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		// По умолчанию используется стратегия least_loaded
+		settings, err := testService.GetTeamSettings(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, "least_loaded", settings.ReviewerStrategy)
+
+		strategy := "round_robin"
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", ReviewerStrategy: &strategy})
+		require.NoError(t, err)
+
+		settings, err = testService.GetTeamSettings(ctx, "backend")
+		require.NoError(t, err)
+		assert.Equal(t, "round_robin", settings.ReviewerStrategy)
+
+		// PR создаётся с ревьюверами по новой стратегии
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-200", PullRequestName: "Round robin", AuthorID: "u1"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+	})
+
+	t.Run("UpdateTeamSettings_InvalidStrategy", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		strategy := "unknown"
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", ReviewerStrategy: &strategy})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown reviewer selection strategy")
+	})
+
+	t.Run("UpdateTeamSettings_ReviewerLimits", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		// Одному ревьюверу на PR в команде backend
+		maxReviewers := 1
+		settings, err := testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", MaxReviewers: &maxReviewers})
+		require.NoError(t, err)
+		assert.Equal(t, 1, settings.MaxReviewers)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-201", PullRequestName: "Docs", AuthorID: "u1"})
+		require.NoError(t, err)
+		assert.Len(t, pr.AssignedReviewers, 1)
+
+		// reviewer_count больше max_reviewers отклоняется
+		reviewerCount := 2
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-202", PullRequestName: "Docs", AuthorID: "u1", ReviewerCount: &reviewerCount})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "outside of team limits")
+
+		// min_reviewers больше max_reviewers недопустим
+		minReviewers := 2
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", MinReviewers: &minReviewers})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid team settings")
+	})
+
+	t.Run("CreatePR_NotEnoughReviewers", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		// В команде frontend у автора только один активный коллега
+		minReviewers := 2
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "frontend", MinReviewers: &minReviewers})
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-203", PullRequestName: "UI", AuthorID: "u5"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "no active replacement candidate")
+	})
+	t.Run("ListTeams_Pagination", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		page, err := testService.ListTeams(ctx, 2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 3, page.Total)
+		require.Len(t, page.Teams, 2)
+		assert.Equal(t, "backend", page.Teams[0].TeamName)
+		assert.Len(t, page.Teams[0].Members, 4)
+		assert.Equal(t, "devops", page.Teams[1].TeamName)
+
+		page, err = testService.ListTeams(ctx, 2, 2)
+		require.NoError(t, err)
+		require.Len(t, page.Teams, 1)
+		assert.Equal(t, "frontend", page.Teams[0].TeamName)
+	})
+
+	t.Run("RenameTeam_CascadesToUsers", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		maxReviewers := 1
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", MaxReviewers: &maxReviewers})
+		require.NoError(t, err)
+
+		team, err := testService.RenameTeam(ctx, dto.RenameTeamRequest{TeamName: "backend", NewTeamName: "platform"})
+		require.NoError(t, err)
+		assert.Equal(t, "platform", team.TeamName)
+		assert.Len(t, team.Members, 4)
+
+		user, err := testRepo.GetUser(ctx, "u1")
+		require.NoError(t, err)
+		assert.Equal(t, "platform", user.TeamName)
+
+		// Настройки переходят к новому имени
+		settings, err := testService.GetTeamSettings(ctx, "platform")
+		require.NoError(t, err)
+		assert.Equal(t, 1, settings.MaxReviewers)
+
+		_, err = testService.RenameTeam(ctx, dto.RenameTeamRequest{TeamName: "platform", NewTeamName: "frontend"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "team already exists")
+	})
+
+	t.Run("UpdateTeamMembers_AddAndMove", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		result, err := testService.UpdateTeamMembers(ctx, dto.UpdateTeamMembersRequest{
+			TeamName: "backend",
+			Add: []models.TeamMember{
+				{UserID: "u20", Username: "Nina", IsActive: true},
+				{UserID: "u5", Username: "Eve", IsActive: true},
+			},
+		})
+		require.NoError(t, err)
+		assert.Len(t, result.Team.Members, 6)
+
+		// u5 переведён из frontend
+		user, err := testRepo.GetUser(ctx, "u5")
+		require.NoError(t, err)
+		assert.Equal(t, "backend", user.TeamName)
+	})
+
+	t.Run("UpdateTeamMembers_RemoveWithOpenPRs", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		maxReviewers := 1
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", MaxReviewers: &maxReviewers})
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-210", PullRequestName: "Remove", AuthorID: "u1"})
+		require.NoError(t, err)
+		require.Len(t, pr.AssignedReviewers, 1)
+		removed := pr.AssignedReviewers[0]
+
+		// По умолчанию удаление ревьювера открытого PR запрещено
+		_, err = testService.UpdateTeamMembers(ctx, dto.UpdateTeamMembersRequest{TeamName: "backend", Remove: []string{removed}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "open pull requests")
+
+		result, err := testService.UpdateTeamMembers(ctx, dto.UpdateTeamMembersRequest{
+			TeamName:     "backend",
+			Remove:       []string{removed},
+			OpenPRPolicy: "reassign",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{removed}, result.RemovedUserIDs)
+		require.Len(t, result.PRChanges, 1)
+		require.Len(t, result.PRChanges[0].After, 1)
+		assert.NotEqual(t, removed, result.PRChanges[0].After[0])
+
+		_, err = testRepo.GetUser(ctx, removed)
+		require.Error(t, err)
+	})
+
+	t.Run("DeleteTeam_ClosePolicy", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-211", PullRequestName: "Frontend", AuthorID: "u5"})
+		require.NoError(t, err)
+
+		_, err = testService.DeleteTeam(ctx, dto.DeleteTeamRequest{TeamName: "frontend"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "open pull requests")
+
+		result, err := testService.DeleteTeam(ctx, dto.DeleteTeamRequest{TeamName: "frontend", OpenPRPolicy: "close"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u5", "u6"}, result.DeletedUserIDs)
+		assert.Equal(t, []string{"pr-211"}, result.ClosedPRs)
+
+		_, err = testService.GetTeam(ctx, "frontend")
+		require.Error(t, err)
+
+		// PR остаётся в истории без автора
+		pr, err := testRepo.GetPR(ctx, "pr-211")
+		require.NoError(t, err)
+		assert.Equal(t, "CLOSED", pr.Status)
+		assert.Empty(t, pr.AuthorID)
+	})
+
+	t.Run("DeleteTeam_ReassignPolicy", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-212", PullRequestName: "Devops", AuthorID: "u7"})
+		require.NoError(t, err)
+
+		result, err := testService.DeleteTeam(ctx, dto.DeleteTeamRequest{TeamName: "devops", OpenPRPolicy: "reassign", NewAuthorID: "u1"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"pr-212"}, result.TransferredPRs)
+
+		// Ревьюверы из удалённой команды заменены участниками команды нового автора
+		pr, err := testRepo.GetPR(ctx, "pr-212")
+		require.NoError(t, err)
+		assert.Equal(t, "u1", pr.AuthorID)
+		assert.NotEmpty(t, pr.AssignedReviewers)
+		assert.Subset(t, []string{"u2", "u3"}, pr.AssignedReviewers)
+	})
 }
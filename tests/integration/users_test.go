@@ -2,6 +2,8 @@ package integration
 
 import (
 	"context"
+	"pr_task/internal/dto"
+	models "pr_task/internal/model"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -43,7 +45,7 @@ func TestUserIntegration(t *testing.T) {
 		require.NoError(t, err)
 
 		// Создаем PR с ревьюверами
-		pr, err := testService.CreatePullRequest(ctx, "pr-100", "Test PR", "u1")
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-100", PullRequestName: "Test PR", AuthorID: "u1"})
 		require.NoError(t, err)
 		assert.Contains(t, pr.AssignedReviewers, "u2") // u2 должен быть назначен ревьювером
 
@@ -74,4 +76,144 @@ func TestUserIntegration(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
 	})
+	t.Run("MoveUser_ReassignsOpenReviews", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		maxReviewers := 1
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", MaxReviewers: &maxReviewers})
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-101", PullRequestName: "Move", AuthorID: "u1"})
+		require.NoError(t, err)
+		require.Len(t, pr.AssignedReviewers, 1)
+		moved := pr.AssignedReviewers[0]
+
+		result, err := testService.MoveUser(ctx, dto.MoveUserRequest{UserID: moved, TeamName: "frontend"})
+		require.NoError(t, err)
+		assert.Equal(t, "backend", result.FromTeam)
+		assert.Equal(t, "frontend", result.User.TeamName)
+		require.Len(t, result.Reassignments, 1)
+		assert.Equal(t, "pr-101", result.Reassignments[0].PRID)
+		assert.Equal(t, moved, result.Reassignments[0].OldReviewerID)
+
+		// Ревью передано оставшемуся активному участнику backend
+		updated, err := testRepo.GetPR(ctx, "pr-101")
+		require.NoError(t, err)
+		assert.Equal(t, []string{result.Reassignments[0].NewReviewerID}, updated.AssignedReviewers)
+		assert.NotContains(t, []string{"u1", moved}, result.Reassignments[0].NewReviewerID)
+	})
+
+	t.Run("MoveUser_KeepReviews", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-102", PullRequestName: "Keep", AuthorID: "u1"})
+		require.NoError(t, err)
+
+		result, err := testService.MoveUser(ctx, dto.MoveUserRequest{UserID: "u2", TeamName: "devops", ReviewPolicy: "keep"})
+		require.NoError(t, err)
+		assert.Empty(t, result.Reassignments)
+
+		updated, err := testRepo.GetPR(ctx, "pr-102")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, pr.AssignedReviewers, updated.AssignedReviewers)
+	})
+
+	t.Run("MoveUser_TeamNotFound", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.MoveUser(ctx, dto.MoveUserRequest{UserID: "u2", TeamName: "nonexistent"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+	t.Run("ListUsers_FiltersAndCursor", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		isActive := true
+		page, err := testService.ListUsers(ctx, models.UserFilter{TeamName: "backend", IsActive: &isActive, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page.Users, 2)
+		assert.Equal(t, "u1", page.Users[0].UserID)
+		assert.Equal(t, "u2", page.Users[1].UserID)
+		assert.Equal(t, "u2", page.NextCursor)
+
+		// Последняя страница без курсора, неактивный u4 отфильтрован
+		page, err = testService.ListUsers(ctx, models.UserFilter{TeamName: "backend", IsActive: &isActive, Cursor: page.NextCursor, Limit: 2})
+		require.NoError(t, err)
+		require.Len(t, page.Users, 1)
+		assert.Equal(t, "u3", page.Users[0].UserID)
+		assert.Empty(t, page.NextCursor)
+
+		page, err = testService.ListUsers(ctx, models.UserFilter{Username: "AR", Limit: 20})
+		require.NoError(t, err)
+		require.Len(t, page.Users, 1)
+		assert.Equal(t, "Charlie", page.Users[0].Username)
+	})
+
+	t.Run("UpdateUser_Username", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		user, err := testService.UpdateUser(ctx, dto.UpdateUserRequest{UserID: "u1", Username: "Alicia"})
+		require.NoError(t, err)
+		assert.Equal(t, "Alicia", user.Username)
+		assert.Equal(t, "backend", user.TeamName)
+
+		_, err = testService.UpdateUser(ctx, dto.UpdateUserRequest{UserID: "nonexistent", Username: "Nobody"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("DeleteUser_OpenPRPolicy", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		_, err = testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-103", PullRequestName: "Delete", AuthorID: "u1"})
+		require.NoError(t, err)
+
+		_, err = testService.DeleteUser(ctx, dto.DeleteUserRequest{UserID: "u1"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "open pull requests")
+
+		result, err := testService.DeleteUser(ctx, dto.DeleteUserRequest{UserID: "u1", OpenPRPolicy: "close"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"pr-103"}, result.ClosedPRs)
+
+		_, err = testService.GetUser(ctx, "u1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("DeleteUser_ReviewerIsReplaced", func(t *testing.T) {
+		clearTestData()
+		err := setupTestData(ctx)
+		require.NoError(t, err)
+
+		maxReviewers := 1
+		_, err = testService.UpdateTeamSettings(ctx, dto.TeamSettingsRequest{TeamName: "backend", MaxReviewers: &maxReviewers})
+		require.NoError(t, err)
+
+		pr, err := testService.CreatePullRequest(ctx, dto.CreatePullRequestRequest{PullRequestID: "pr-104", PullRequestName: "Reviewer", AuthorID: "u1"})
+		require.NoError(t, err)
+		reviewer := pr.AssignedReviewers[0]
+
+		// Ревьюверу без собственных PR политика не нужна
+		result, err := testService.DeleteUser(ctx, dto.DeleteUserRequest{UserID: reviewer})
+		require.NoError(t, err)
+		require.Len(t, result.PRChanges, 1)
+
+		updated, err := testRepo.GetPR(ctx, "pr-104")
+		require.NoError(t, err)
+		require.Len(t, updated.AssignedReviewers, 1)
+		assert.NotEqual(t, reviewer, updated.AssignedReviewers[0])
+	})
 }